@@ -0,0 +1,120 @@
+package main
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"boards-merger/internal/utils/logger"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Exit codes of the diff subcommand, following diff(1)
+const (
+	diffIdentical   = 0
+	diffDifferences = 1
+	diffFailure     = 2
+)
+
+// runDiff compares two catalogs, each given as a directory of board files or a single file such as a previously merged output
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cli_boards_merger diff [flags] <old directory or file> <new directory or file>")
+		flags.PrintDefaults()
+	}
+	recursiveFlag := flags.Bool("r", false, "Enable recursive directory traversal (default: disabled)")
+	loggingFlag := flags.Bool("l", false, "Enable logs (default: disabled)")
+	depthFlag := flags.Int("depth", 10, "Maximum depth for directory traversal, used only when recursive is set")
+	strategyFlag := flags.String("strategy", string(model.LastWins), "Conflict resolution used to merge each catalog, as for the merge command")
+	formatFlag := flags.String("format", "text", "Output format: text or json")
+	mergeFlags := core.RegisterMergeFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return diffFailure
+	}
+	if *formatFlag != "text" && *formatFlag != "json" {
+		fmt.Printf("unknown diff format '%v', supported formats: text, json\n", *formatFlag)
+		return diffFailure
+	}
+
+	strategy, err := model.ParseMergeStrategy(*strategyFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		return diffFailure
+	}
+
+	mergeOptions, err := mergeFlags.Options()
+	if err != nil {
+		fmt.Println(err.Error())
+		return diffFailure
+	}
+	mergeOptions.Strategy = strategy
+
+	if *loggingFlag {
+		logger.Enable()
+	} else {
+		logger.Disable()
+	}
+
+	depth := *depthFlag
+	if !*recursiveFlag {
+		depth = 0
+	}
+
+	catalogs := make([]*model.BoardsInfo, 2)
+	for i, path := range flags.Args() {
+		collector := diagnostics.NewCollector()
+		readOptions := core.ReadOptions{Recursive: *recursiveFlag, MaxDepth: depth, Diagnostics: collector}
+		mergeOptions.Diagnostics = collector
+		catalogs[i], err = core.LoadCatalog(path, readOptions, mergeOptions)
+		printDiagnosticsSummary(collector, !*loggingFlag)
+		if err != nil {
+			fmt.Println(err.Error())
+			return diffFailure
+		}
+	}
+
+	diff := core.DiffBoardsWithIdentity(catalogs[0], catalogs[1], mergeOptions.Identity)
+	if err := writeDiff(diff, *formatFlag); err != nil {
+		fmt.Println(err.Error())
+		return diffFailure
+	}
+
+	if diff.Empty() {
+		return diffIdentical
+	}
+	return diffDifferences
+}
+
+func writeDiff(diff model.BoardsDiff, format string) error {
+	if format == "text" {
+		fmt.Println(diff.Summary())
+		for _, line := range diff.Details() {
+			fmt.Println(line)
+		}
+		return nil
+	}
+
+	// Empty lists are written as [] rather than null
+	if diff.Added == nil {
+		diff.Added = []model.Board{}
+	}
+	if diff.Removed == nil {
+		diff.Removed = []model.Board{}
+	}
+	if diff.Changed == nil {
+		diff.Changed = []model.BoardChange{}
+	}
+
+	out, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(out))
+	return err
+}
@@ -2,20 +2,104 @@ package main
 
 import (
 	"boards-merger/internal/core"
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/encoders"
+	"boards-merger/internal/model"
+	"boards-merger/internal/query"
+	"boards-merger/internal/schema"
 	"boards-merger/internal/utils/logger"
+	"boards-merger/internal/watch"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 func main() {
-	dirPathFlag := flag.String("path", "", "Path to the directory containing JSON files")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			os.Exit(runDiff(os.Args[2:]))
+		case "validate":
+			os.Exit(runValidate(os.Args[2:]))
+		case "stats":
+			os.Exit(runStats(os.Args[2:]))
+		case "schema":
+			os.Stdout.Write(schema.Builtin)
+			return
+		}
+	}
+
+	dirPathFlag := flag.String("path", "", "Path to the directory containing JSON, YAML, CSV or TSV files")
 	recursiveFlag := flag.Bool("r", false, "Enable recursive directory traversal (default: disabled)")
 	loggingFlag := flag.Bool("l", false, "Enable logs (default: disabled)")
 	depthFlag := flag.Int("depth", 10, "Maximum depth for directory traversal, used only when recursive is set")
+	formatFlag := flag.String("format", "json", "Output format: "+strings.Join(encoders.Names(), ", "))
+	strategyFlag := flag.String("strategy", string(model.LastWins), "Conflict resolution for duplicate boards: first-wins, last-wins, fail-on-conflict or prefer-non-empty, "+
+		"followed by optional per-field overrides, e.g. 'first-wins,core=fail-on-conflict'")
+	conflictsOutFlag := flag.String("conflicts-out", "", "Path of a JSON file to write the conflicts report to")
+	sourcesFlag := flag.Bool("sources", false, "Include the source file and index of every board field under '_sources' (default: disabled)")
+	workersFlag := flag.Int("workers", 0, "Number of files read and parsed concurrently (default: one per CPU)")
+	schemaFlag := flag.Bool("schema", false, "Skip files that do not match the built-in boards JSON Schema (default: disabled)")
+	schemaFileFlag := flag.String("schema-file", "", "Path of a JSON Schema applied to every board on top of the built-in schema, implies -schema")
+	mergeFlags := core.RegisterMergeFlags(flag.CommandLine)
+	sortFlag := flag.String("sort", "", "Comma separated sort keys, board fields or extra properties optionally followed by ':desc', e.g. 'vendor,revision:desc', compared regardless of case and with numbers by value (default: identity order)")
+	groupFlag := flag.Bool("group-by-vendor", false, "Nest the boards under their vendor in the JSON & YAML output (default: disabled)")
+	extendedMetaDataFlag := flag.Bool("extended-metadata", false, "Add per-vendor, core & WiFi counts, file counts, merged duplicates, generation time, version and a content hash to '_metadata' (default: disabled)")
+	whereFlag := flag.String("where", "", "Keep only the boards matching a filter expression, e.g. 'vendor == \"Espressif\" && has_wifi && core =~ \"ESP32.*\"'")
+	outputFlag := flag.String("o", "", "Path of the file to write the merged output to (default: stdout)")
+	watchFlag := flag.Bool("watch", false, "Watch the board files and rewrite the output file (-o) whenever they change (default: disabled)")
+	intervalFlag := flag.Duration("interval", time.Second, "Polling interval of the watch mode")
+	debounceFlag := flag.Duration("debounce", 500*time.Millisecond, "Time without further changes before the watch mode merges again")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: cli_boards_merger [flags]\n       cli_boards_merger diff [flags] <old directory or file> <new directory or file>\n       cli_boards_merger validate [flags] <directory or file>...\n       cli_boards_merger stats [flags] <directory or file>\n       cli_boards_merger schema    (prints the built-in boards JSON Schema)")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
+	encoder, err := encoders.Get(*formatFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	strategy, err := model.ParseMergeStrategy(*strategyFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	validator, err := loadSchema(*schemaFlag, *schemaFileFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	mergeOptions, err := mergeFlags.Options()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	sortOrder, err := model.ParseSortOrder(*sortFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	var where *query.Expression
+	if len(*whereFlag) > 0 {
+		if where, err = query.Parse(*whereFlag); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+
 	dirPath := *dirPathFlag
 	if len(dirPath) == 0 {
 		fmt.Print("Enter the path to the directory: ")
@@ -34,19 +118,165 @@ func main() {
 		logger.Disable()
 	}
 
-	jsonList, err := core.ReadDirectory(dirPath, recursive, depth)
+	readOptions := core.ReadOptions{Recursive: recursive, MaxDepth: depth}
+	mergeOptions.Strategy = strategy
+	mergeOptions.Provenance = *sourcesFlag
+	mergeOptions.Workers = *workersFlag
+	mergeOptions.Schema = validator
+	mergeOptions.Sort = sortOrder
+	mergeOptions.GroupByVendor = *groupFlag
+	mergeOptions.ExtendedMetaData = *extendedMetaDataFlag
+	output := outputOptions{path: *outputFlag, encoder: encoder, conflictsPath: *conflictsOutFlag, where: where}
+
+	if *watchFlag {
+		if len(output.path) == 0 {
+			fmt.Println("-watch requires an output file, set with -o")
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		poller := watch.Poller{Interval: *intervalFlag, Debounce: *debounceFlag}
+		watchDirectory(ctx, poller, dirPath, readOptions, mergeOptions, output, !*loggingFlag)
+		return
+	}
+
+	boards, _, err := mergeDirectory(dirPath, readOptions, mergeOptions, output.path, !*loggingFlag)
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 
-	boards, err := core.ProcessJsonFiles(jsonList)
-	if err != nil {
+	if err := output.write(output.filter(boards)); err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
+}
+
+// mergeDirectory reads and merges the board files of the directory, returning the files that were found.
+// The output file is never read back as an input, when written inside the directory.
+func mergeDirectory(dirPath string, readOptions core.ReadOptions, mergeOptions core.MergeOptions, outputPath string, verbose bool) (*model.BoardsInfo, []string, error) {
+	// Findings are already logged when logging is enabled, only their count is printed then
+	collector := diagnostics.NewCollector()
+	readOptions.Diagnostics = collector
+	mergeOptions.Diagnostics = collector
+
+	jsonList, err := core.ReadDirectoryWithOptions(dirPath, readOptions)
+	if err != nil {
+		printDiagnosticsSummary(collector, verbose)
+		return nil, nil, err
+	}
+
+	if len(outputPath) > 0 {
+		jsonList = excludeFile(jsonList, outputPath)
+	}
 
-	out, _ := json.MarshalIndent(boards, "", "  ")
-	os.Stdout.Write(out)
-	fmt.Println()
+	boards, err := core.ProcessJsonFilesWithOptions(jsonList, mergeOptions)
+	printDiagnosticsSummary(collector, verbose)
+	if err != nil {
+		return nil, jsonList, err
+	}
+	return boards, jsonList, nil
+}
+
+// loadSchema returns nil when schema validation is disabled
+func loadSchema(enabled bool, extensionPath string) (*schema.Validator, error) {
+	if !enabled && len(extensionPath) == 0 {
+		return nil, nil
+	}
+	return schema.New(extensionPath)
+}
+
+func excludeFile(paths []string, excluded string) []string {
+	excluded, err := filepath.Abs(excluded)
+	if err != nil {
+		return paths
+	}
+
+	result := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path != excluded {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+type outputOptions struct {
+	// path of the merged output, stdout when empty
+	path          string
+	encoder       encoders.Encoder
+	conflictsPath string
+	// where keeps only the matching boards, it may be nil
+	where *query.Expression
+}
+
+// filter keeps the boards matching the where expression, their metadata is recomputed
+func (output outputOptions) filter(boards *model.BoardsInfo) *model.BoardsInfo {
+	if output.where == nil {
+		return boards
+	}
+	return boards.Filter(output.where.Match)
+}
+
+func (output outputOptions) write(boards *model.BoardsInfo) error {
+	if len(output.conflictsPath) > 0 {
+		if err := writeConflicts(output.conflictsPath, boards.Conflicts); err != nil {
+			return err
+		}
+	}
+
+	if len(output.path) == 0 {
+		return output.encoder.Encode(os.Stdout, boards)
+	}
+
+	// The output is replaced in a single rename, so readers never see a partially written file
+	file, err := os.CreateTemp(filepath.Dir(output.path), filepath.Base(output.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write output: %v", err.Error())
+	}
+	defer os.Remove(file.Name())
+
+	if err := output.encoder.Encode(file, boards); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write output: %v", err.Error())
+	}
+	if err := os.Rename(file.Name(), output.path); err != nil {
+		return fmt.Errorf("failed to write output: %v", err.Error())
+	}
+	return nil
+}
+
+// printDiagnosticsSummary writes warnings and errors to stderr, keeping stdout for the merged output
+func printDiagnosticsSummary(collector *diagnostics.Collector, verbose bool) {
+	findings := collector.Diagnostics(diagnostics.Warning)
+	if len(findings) == 0 {
+		return
+	}
+
+	if verbose {
+		for _, finding := range findings {
+			fmt.Fprintln(os.Stderr, finding.String())
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Finished with %v warning(s) and %v error(s)\n", collector.Count(diagnostics.Warning), collector.Count(diagnostics.Error))
+}
+
+func writeConflicts(path string, conflicts []model.Conflict) error {
+	if conflicts == nil {
+		conflicts = []model.Conflict{}
+	}
+
+	out, err := json.MarshalIndent(conflicts, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write conflicts report: %v", err.Error())
+	}
+	return nil
 }
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"boards-merger/internal/query"
+	"boards-merger/internal/utils/logger"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// runStats prints the extended metadata of a catalog, given as a directory of board files or a single file
+func runStats(args []string) int {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cli_boards_merger stats [flags] <directory or file>")
+		flags.PrintDefaults()
+	}
+	recursiveFlag := flags.Bool("r", false, "Enable recursive directory traversal (default: disabled)")
+	loggingFlag := flags.Bool("l", false, "Enable logs (default: disabled)")
+	depthFlag := flags.Int("depth", 10, "Maximum depth for directory traversal, used only when recursive is set")
+	strategyFlag := flags.String("strategy", string(model.LastWins), "Conflict resolution used to merge the catalog, as for the merge command")
+	formatFlag := flags.String("format", "text", "Output format: text or json")
+	mergeFlags := core.RegisterMergeFlags(flags)
+	whereFlag := flags.String("where", "", "Count only the boards matching a filter expression, e.g. 'vendor == \"Espressif\" && has_wifi'")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	if *formatFlag != "text" && *formatFlag != "json" {
+		fmt.Printf("unknown stats format '%v', supported formats: text, json\n", *formatFlag)
+		return 2
+	}
+
+	strategy, err := model.ParseMergeStrategy(*strategyFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 2
+	}
+
+	mergeOptions, err := mergeFlags.Options()
+	if err != nil {
+		fmt.Println(err.Error())
+		return 2
+	}
+
+	var where *query.Expression
+	if len(*whereFlag) > 0 {
+		if where, err = query.Parse(*whereFlag); err != nil {
+			fmt.Println(err.Error())
+			return 2
+		}
+	}
+
+	if *loggingFlag {
+		logger.Enable()
+	} else {
+		logger.Disable()
+	}
+
+	depth := *depthFlag
+	if !*recursiveFlag {
+		depth = 0
+	}
+
+	collector := diagnostics.NewCollector()
+	readOptions := core.ReadOptions{Recursive: *recursiveFlag, MaxDepth: depth, Diagnostics: collector}
+	mergeOptions.Strategy = strategy
+	mergeOptions.ExtendedMetaData = true
+	mergeOptions.Diagnostics = collector
+	boards, err := core.LoadCatalog(flags.Arg(0), readOptions, mergeOptions)
+	printDiagnosticsSummary(collector, !*loggingFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	if where != nil {
+		boards = boards.Filter(where.Match)
+	}
+
+	if err := writeStats(os.Stdout, boards.MetaData, *formatFlag); err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+	return 0
+}
+
+func writeStats(w io.Writer, metadata model.MetaData, format string) error {
+	if format == "json" {
+		out, err := json.MarshalIndent(metadata, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	}
+
+	fmt.Fprintf(w, "Boards: %v from %v vendor(s)\n", metadata.TotalBoards, metadata.UniqueVendors)
+	fmt.Fprintf(w, "Files: %v scanned, %v parsed, %v skipped\n", metadata.Files.Scanned, metadata.Files.Parsed, metadata.Files.Skipped)
+	fmt.Fprintf(w, "Merged duplicates: %v\n", metadata.MergedDuplicates)
+	fmt.Fprintf(w, "WiFi: %v with, %v without, %v unknown\n", metadata.WiFi.With, metadata.WiFi.Without, metadata.WiFi.Unknown)
+	writeCounts(w, "Boards per vendor", metadata.BoardsPerVendor)
+	writeCounts(w, "Cores", metadata.Cores)
+	fmt.Fprintf(w, "Content hash: %v\n", metadata.ContentHash)
+	_, err := fmt.Fprintf(w, "Generated at %v by version %v\n", metadata.GeneratedAt.Format(time.RFC3339), metadata.ToolVersion)
+	return err
+}
+
+// writeCounts lists the counts from the highest to the lowest, then by key
+func writeCounts(w io.Writer, title string, counts map[string]int) {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i int, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	fmt.Fprintf(w, "%v:\n", title)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %v: %v\n", key, counts[key])
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/utils/logger"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Exit codes of the validate subcommand
+const (
+	validatePassed  = 0
+	validateFailed  = 1
+	validateFailure = 2
+)
+
+// runValidate checks board files without producing merged output, the exit code fails the check
+// when a finding reaches the -fail-on severity
+func runValidate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cli_boards_merger validate [flags] <directory or file>...")
+		flags.PrintDefaults()
+	}
+	recursiveFlag := flags.Bool("r", false, "Enable recursive directory traversal (default: disabled)")
+	loggingFlag := flags.Bool("l", false, "Enable logs (default: disabled)")
+	depthFlag := flags.Int("depth", 10, "Maximum depth for directory traversal, used only when recursive is set")
+	severityFlag := flags.String("severity", "", "Comma separated severity overrides per finding code, e.g. 'duplicate-in-file=error,invalid-type=off'")
+	failOnFlag := flags.String("fail-on", "error", "Minimum severity of a finding failing the validation: info, warning or error")
+	formatFlag := flags.String("format", "text", "Output format: text or json")
+	schemaFlag := flags.Bool("schema", false, "Validate every file against the built-in boards JSON Schema (default: disabled)")
+	schemaFileFlag := flags.String("schema-file", "", "Path of a JSON Schema applied to every board on top of the built-in schema, implies -schema")
+	mergeFlags := core.RegisterDecodeFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return validateFailure
+	}
+	if *formatFlag != "text" && *formatFlag != "json" {
+		fmt.Printf("unknown validate format '%v', supported formats: text, json\n", *formatFlag)
+		return validateFailure
+	}
+
+	overrides, err := diagnostics.ParseSeverityOverrides(*severityFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		return validateFailure
+	}
+
+	validator, err := loadSchema(*schemaFlag, *schemaFileFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		return validateFailure
+	}
+
+	mergeOptions, err := mergeFlags.Options()
+	if err != nil {
+		fmt.Println(err.Error())
+		return validateFailure
+	}
+
+	var failOn diagnostics.Severity
+	if err := failOn.UnmarshalText([]byte(*failOnFlag)); err != nil || failOn == diagnostics.Off {
+		fmt.Printf("invalid -fail-on severity '%v'\n", *failOnFlag)
+		return validateFailure
+	}
+
+	if *loggingFlag {
+		logger.Enable()
+	} else {
+		logger.Disable()
+	}
+
+	depth := *depthFlag
+	if !*recursiveFlag {
+		depth = 0
+	}
+
+	collector := diagnostics.NewCollector()
+	var files []string
+	for _, path := range flags.Args() {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			return validateFailure
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		found, err := core.ReadDirectoryWithOptions(path, core.ReadOptions{Recursive: *recursiveFlag, MaxDepth: depth, Diagnostics: collector})
+		if err != nil {
+			fmt.Println(err.Error())
+			return validateFailure
+		}
+		files = append(files, found...)
+	}
+
+	if err := core.ValidateFiles(context.Background(), files, core.ValidateOptions{Schema: validator, Aliases: mergeOptions.Aliases, Coercion: mergeOptions.Coercion, Identity: mergeOptions.Identity, Diagnostics: collector}); err != nil {
+		fmt.Println(err.Error())
+		return validateFailure
+	}
+	collector.Override(overrides)
+
+	findings := collector.Diagnostics(diagnostics.Info)
+	if *formatFlag == "json" {
+		if findings == nil {
+			findings = []diagnostics.Diagnostic{}
+		}
+		out, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			fmt.Println(err.Error())
+			return validateFailure
+		}
+		fmt.Println(string(out))
+	} else {
+		for _, finding := range findings {
+			fmt.Println(finding.String())
+		}
+		fmt.Printf("Checked %v files: %v error(s), %v warning(s), %v info\n", len(files), collector.Count(diagnostics.Error), collector.Count(diagnostics.Warning), collector.Count(diagnostics.Info))
+	}
+
+	if len(collector.Diagnostics(failOn)) > 0 {
+		return validateFailed
+	}
+	return validatePassed
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"boards-merger/internal/utils/testutils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunValidateExitCode(t *testing.T) {
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	validPath := filepath.Join(dir, "valid.json")
+	testutils.WriteToFile(t, validPath, `{"boards": [{"name": "Board1", "vendor": "VendorA"}]}`)
+	unnamedPath := filepath.Join(dir, "unnamed.json")
+	testutils.WriteToFile(t, unnamedPath, `{"boards": [{"vendor": "VendorA"}]}`)
+	malformedPath := filepath.Join(dir, "malformed.json")
+	testutils.WriteToFile(t, malformedPath, `{"boards": [`)
+	mistypedPath := filepath.Join(dir, "mistyped.json")
+	testutils.WriteToFile(t, mistypedPath, `{"boards": [{"name": "Board1", "vendor": "VendorA", "has_wifi": "maybe"}]}`)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{name: "Valid board", args: []string{validPath}, expected: validatePassed},
+		{name: "Board without name", args: []string{unnamedPath}, expected: validateFailed},
+		{name: "Board without name downgraded", args: []string{"-severity", "invalid-board=warning", unnamedPath}, expected: validatePassed},
+		{name: "Malformed file", args: []string{malformedPath}, expected: validateFailed},
+		{name: "Schema violation", args: []string{"-schema", mistypedPath}, expected: validateFailed},
+		{name: "Missing file", args: []string{filepath.Join(dir, "missing.json")}, expected: validateFailure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if code := runValidate(append([]string{"-format", "json"}, test.args...)); code != test.expected {
+				t.Fatalf("Unexpected exit code: got %v, expected %v", code, test.expected)
+			}
+		})
+	}
+}
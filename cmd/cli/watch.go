@@ -0,0 +1,58 @@
+package main
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/model"
+	"boards-merger/internal/watch"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// watchDirectory merges the directory and rewrites the output on every change until ctx is cancelled.
+// A failed merge is reported and the previous output is kept.
+func watchDirectory(ctx context.Context, poller watch.Poller, dirPath string, readOptions core.ReadOptions, mergeOptions core.MergeOptions, output outputOptions, verbose bool) {
+	root, err := filepath.Abs(dirPath)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	var previous *model.BoardsInfo
+	var files []string
+	run := func() {
+		boards, jsonList, err := mergeDirectory(dirPath, readOptions, mergeOptions, output.path, verbose)
+		// The directory could not be read, the previously found files stay watched
+		if jsonList != nil {
+			files = jsonList
+		}
+		if err == nil {
+			boards = output.filter(boards)
+			err = output.write(boards)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%v] %v\n", time.Now().Format(time.TimeOnly), err.Error())
+			return
+		}
+
+		if previous == nil {
+			fmt.Fprintf(os.Stderr, "[%v] Merged %v boards into %v\n", time.Now().Format(time.TimeOnly), len(boards.Boards), output.path)
+		} else {
+			diff := core.DiffBoardsWithIdentity(previous, boards, mergeOptions.Identity)
+			fmt.Fprintf(os.Stderr, "[%v] Merged %v boards into %v: %v\n", time.Now().Format(time.TimeOnly), len(boards.Boards), output.path, diff.Summary())
+			for _, line := range diff.Details() {
+				fmt.Fprintln(os.Stderr, "  "+line)
+			}
+		}
+		previous = boards
+	}
+
+	run()
+	fmt.Fprintf(os.Stderr, "Watching %v for changes, press Ctrl+C to stop\n", dirPath)
+	// Directories are walked on every poll, so files added to a new subdirectory are noticed
+	poller.Run(ctx, func() []string {
+		return append(watch.Paths(root, files), watch.Directories(root, readOptions.MaxDepth)...)
+	}, run)
+}
@@ -1,17 +1,46 @@
 package main
 
 import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/model"
 	"boards-merger/internal/web"
 	"flag"
 	"fmt"
+	"time"
 )
 
 func main() {
-	port := flag.String("port", "8080", "Port number for the web server")
+	var config web.Config
+	flag.StringVar(&config.Port, "port", "8080", "Port number for the web server")
+	flag.Var(&config.Roots, "root", "Allowed root directory as 'name=path', can be repeated (default: current working directory)")
+	flag.DurationVar(&config.Timeout, "timeout", 30*time.Second, "Maximum time spent processing a directory per request, 0 disables it")
+	flag.DurationVar(&config.Watch.Interval, "watch-interval", 2*time.Second, "Polling interval of the processed directories for live table updates, 0 disables them")
+	flag.DurationVar(&config.Watch.Debounce, "watch-debounce", time.Second, "Time without further changes before a live table update is sent")
+	mergeFlags := core.RegisterMergeFlags(flag.CommandLine)
+	sortFlag := flag.String("sort", "", "Comma separated sort keys, board fields or extra properties optionally followed by ':desc', e.g. 'vendor,revision:desc', compared regardless of case and with numbers by value (default: identity order)")
+	flag.BoolVar(&config.GroupByVendor, "group-by-vendor", false, "Nest the boards under their vendor in the JSON & YAML API output (default: disabled)")
+	flag.BoolVar(&config.ExtendedMetaData, "extended-metadata", false, "Add per-vendor, core & WiFi counts, file counts, merged duplicates, generation time, version and a content hash to '_metadata' of the API responses (default: disabled)")
 	flag.Parse()
 
-	fmt.Printf("Starting web server on port %v", *port)
-	if err := web.StartWebServer(*port); err != nil {
-		fmt.Printf("Failed to start web server on port %v: %v", *port, err.Error())
+	mergeOptions, err := mergeFlags.Options()
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	config.Aliases = mergeOptions.Aliases
+	config.Coercion = mergeOptions.Coercion
+	config.Vendors = mergeOptions.Vendors
+	config.Identity = mergeOptions.Identity
+	config.Similarity = mergeOptions.Similarity
+
+	config.Sort, err = model.ParseSortOrder(*sortFlag)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Printf("Starting web server on port %v", config.Port)
+	if err := web.StartWebServer(config); err != nil {
+		fmt.Printf("Failed to start web server on port %v: %v", config.Port, err.Error())
 	}
 }
@@ -1,3 +1,8 @@
 module boards-merger
 
 go 1.23.2
+
+require (
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	gopkg.in/yaml.v3 v3.0.1
+)
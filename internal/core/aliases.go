@@ -0,0 +1,81 @@
+package core
+
+import (
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadFieldAliases combines the built-in aliases, when defaults is set, with the aliases of a JSON file grouped by
+// board field, e.g. {"vendor": ["make"]}. The path may be empty, nil is returned when no key can be renamed.
+func LoadFieldAliases(path string, defaults bool, ignoreCase bool) (*model.FieldAliases, error) {
+	aliases := make(map[string][]string)
+	if defaults {
+		for field, fieldAliases := range model.DefaultAliases {
+			aliases[field] = append(aliases[field], fieldAliases...)
+		}
+	}
+
+	if len(path) > 0 {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read aliases file: %v", err.Error())
+		}
+
+		var fileAliases map[string][]string
+		if err := json.Unmarshal(data, &fileAliases); err != nil {
+			return nil, fmt.Errorf("invalid aliases file %v: %v", path, err.Error())
+		}
+		for field, fieldAliases := range fileAliases {
+			aliases[field] = append(aliases[field], fieldAliases...)
+		}
+	}
+
+	if len(aliases) == 0 && !ignoreCase {
+		return nil, nil
+	}
+	return model.NewFieldAliases(aliases, ignoreCase)
+}
+
+// aliasReport gathers the aliases applied to a file, reported once per file at the position of their first use
+type aliasReport struct {
+	renamed map[string]string
+	applied []model.AppliedAlias
+	first   map[model.AppliedAlias]string
+}
+
+func newAliasReport() *aliasReport {
+	return &aliasReport{renamed: make(map[string]string), first: make(map[model.AppliedAlias]string)}
+}
+
+func (report *aliasReport) add(pointer string, applied []model.AppliedAlias) {
+	for _, alias := range applied {
+		original := pointer + "/" + escapePointer(alias.Alias)
+		report.renamed[pointer+"/"+escapePointer(alias.Field)] = original
+		if _, exists := report.first[alias]; !exists {
+			report.first[alias] = original
+			report.applied = append(report.applied, alias)
+		}
+	}
+}
+
+func (report *aliasReport) emit(path string, position diagnostics.Position, collector *diagnostics.Collector) {
+	mappings := make([]string, len(report.applied))
+	for i, alias := range report.applied {
+		mappings[i] = fmt.Sprintf("'%v' to '%v'", alias.Alias, alias.Field)
+	}
+	collector.InfoAt(diagnostics.CodeFieldAlias, path, diagnostics.NoIndex, position, "Mapped field aliases: %v", strings.Join(mappings, ", "))
+}
+
+// renamedLocator positions the renamed fields at the keys they were read from
+func renamedLocator(locate diagnostics.Locator, renamed map[string]string) diagnostics.Locator {
+	return func(pointer string) diagnostics.Position {
+		if original, exists := renamed[pointer]; exists {
+			pointer = original
+		}
+		return locate.Of(pointer)
+	}
+}
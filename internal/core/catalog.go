@@ -0,0 +1,34 @@
+package core
+
+import (
+	"boards-merger/internal/model"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// LoadCatalog returns the merged boards of a directory, or of a single file such as a previously emitted merged output
+func LoadCatalog(path string, readOptions ReadOptions, mergeOptions MergeOptions) (*model.BoardsInfo, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrPermission) {
+		return nil, fmt.Errorf("%w: %v", fs.ErrPermission, path)
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, path)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = ReadDirectoryWithOptions(path, readOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	boards, err := ProcessJsonFilesWithOptions(files, mergeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %v: %w", path, err)
+	}
+
+	return boards, nil
+}
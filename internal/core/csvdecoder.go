@@ -0,0 +1,177 @@
+package core
+
+import (
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"boards-merger/internal/schema"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Spreadsheet headers such as "Has WiFi" or "Vendor" are mapped onto the canonical board keys
+var csvFieldHeaders = map[string]string{
+	"name":     "name",
+	"vendor":   "vendor",
+	"core":     "core",
+	"has_wifi": "has_wifi",
+}
+
+func normalizeHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(header)
+}
+
+func decodeCsv(path string, data []byte, delimiter rune, collector *diagnostics.Collector, options decodeOptions) (*model.BoardsInfo, diagnostics.Locator, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.TrimLeadingSpace = true
+
+	// Rows are located as "/boards/<row>" and their cells as "/boards/<row>/<column>", like the equivalent JSON boards list
+	positions := make(map[string]diagnostics.Position)
+	locate := mapLocator(positions)
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, locate, &diagnostics.PositionError{Position: csvErrorPosition(err), Err: fmt.Errorf("failed to read header row: %v", err.Error())}
+	}
+
+	columns := make([]string, len(headers))
+	fieldColumns := make(map[string]bool)
+	for i, header := range headers {
+		if field, exists := csvFieldHeaders[normalizeHeader(header)]; exists {
+			columns[i] = field
+			fieldColumns[field] = true
+		} else {
+			columns[i] = strings.TrimSpace(header)
+		}
+	}
+
+	// Aliased headers are renamed once the field headers are known, a field header always takes precedence
+	var applied []model.AppliedAlias
+	var aliasPosition diagnostics.Position
+	for i, header := range headers {
+		if _, exists := csvFieldHeaders[normalizeHeader(header)]; exists {
+			continue
+		}
+		field, exists := options.aliases.Field(header)
+		if !exists {
+			field, exists = options.aliases.Field(normalizeHeader(header))
+		}
+		if !exists || fieldColumns[field] {
+			continue
+		}
+
+		columns[i] = field
+		fieldColumns[field] = true
+		if len(applied) == 0 {
+			line, column := reader.FieldPos(i)
+			aliasPosition = diagnostics.Position{Line: line, Column: column}
+		}
+		applied = append(applied, model.AppliedAlias{Alias: strings.TrimSpace(header), Field: field})
+	}
+	if len(applied) > 0 {
+		report := newAliasReport()
+		report.add("", applied)
+		report.emit(path, aliasPosition, collector)
+	}
+
+	if !fieldColumns["name"] || !fieldColumns["vendor"] {
+		return nil, locate, &diagnostics.PositionError{Position: diagnostics.Position{Line: 1, Column: 1}, Err: fmt.Errorf("header row must contain 'name' and 'vendor' columns")}
+	}
+
+	var boardsList model.BoardsInfo
+	var violations []schema.Violation
+	for rowIndex := 0; ; rowIndex++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			collector.WarnAt(diagnostics.CodeInvalidBoard, path, rowIndex, csvErrorPosition(err), "Skipping malformed row: %v", parseErr.Err.Error())
+			continue
+		} else if err != nil {
+			return nil, locate, err
+		}
+
+		pointer := fmt.Sprintf("/boards/%d", rowIndex)
+		rawMap := make(map[string]interface{})
+		for i, cell := range record {
+			line, column := reader.FieldPos(i)
+			if i == 0 {
+				positions[pointer] = diagnostics.Position{Line: line, Column: column}
+			}
+
+			cell = strings.TrimSpace(cell)
+			// Empty cells are treated as missing data, so they never override values from other files
+			if len(columns[i]) == 0 || len(cell) == 0 {
+				continue
+			}
+			if _, isString := csvStringFields[columns[i]]; isString {
+				rawMap[columns[i]] = cell
+			} else {
+				rawMap[columns[i]] = inferCellValue(cell)
+			}
+			positions[pointer+"/"+escapePointer(columns[i])] = diagnostics.Position{Line: line, Column: column}
+		}
+
+		reportCoercions(path, rowIndex, pointer, options.coercion.Apply(rawMap), options.flagCoerced, locate, collector)
+
+		// Rows are validated as single board objects
+		if options.validator != nil {
+			violations = append(violations, options.validator.ValidateBoard(rawMap, pointer)...)
+		}
+
+		var board model.Board
+		if err := board.FromMap(rawMap); err != nil {
+			collector.WarnAt(diagnostics.CodeInvalidBoard, path, rowIndex, positions[pointer], "Skipping malformed row: %v", err.Error())
+			continue
+		}
+		board.SetSource(model.Source{File: path, Index: rowIndex})
+		boardsList.Boards = append(boardsList.Boards, board)
+	}
+
+	if err := reportViolations(path, violations, locate, collector); err != nil {
+		return nil, locate, err
+	}
+	return &boardsList, locate, nil
+}
+
+func csvErrorPosition(err error) diagnostics.Position {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return diagnostics.Position{Line: parseErr.Line, Column: parseErr.Column}
+	}
+	return diagnostics.Position{}
+}
+
+// Cells of the string board fields are never inferred, so a board named "8266" or a "8051" core stay strings
+var csvStringFields = map[string]void{"name": {}, "vendor": {}, "core": {}}
+
+// Cells are converted to booleans and numbers when unambiguous, everything else is kept as a string
+func inferCellValue(cell string) interface{} {
+	switch strings.ToLower(cell) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	// Values with leading zeros (e.g. SKUs like "00123") are identifiers rather than numbers
+	if len(cell) > 1 && cell[0] == '0' && cell[1] != '.' {
+		return cell
+	}
+
+	number, err := strconv.ParseFloat(cell, 64)
+	if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+		return cell
+	}
+	return number
+}
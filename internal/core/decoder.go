@@ -0,0 +1,251 @@
+package core
+
+import (
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"boards-merger/internal/schema"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File extensions accepted by the directory walker (matched case insensitively)
+var supportedExtensions = map[string]void{
+	".json": {},
+	".yaml": {},
+	".yml":  {},
+	".csv":  {},
+	".tsv":  {},
+}
+
+func isSupportedFile(path string) bool {
+	_, exists := supportedExtensions[strings.ToLower(filepath.Ext(path))]
+	return exists
+}
+
+// decodeOptions are the decoding settings shared by every file format
+type decodeOptions struct {
+	// validator rejects the whole file on any schema violation, it may be nil
+	validator *schema.Validator
+	// aliases renames non-standard keys to the board fields before validation, it may be nil
+	aliases *model.FieldAliases
+	// coercion converts or rejects mistyped values of the typed fields, once aliases are renamed
+	coercion model.Coercion
+	// flagCoerced reports the converted values as invalid-type warnings rather than type-coercion info,
+	// validation flags every mistyped value of the file whatever the coercion
+	flagCoerced bool
+}
+
+// decodeBoardsFile parses the file according to its extension.
+// The returned locator positions JSON pointers (e.g. "/boards/3/core") within the file, errors are positioned with a diagnostics.PositionError.
+func decodeBoardsFile(path string, data []byte, collector *diagnostics.Collector, options decodeOptions) (*model.BoardsInfo, diagnostics.Locator, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return decodeYaml(path, data, collector, options)
+	case ".csv":
+		return decodeCsv(path, data, ',', collector, options)
+	case ".tsv":
+		return decodeCsv(path, data, '\t', collector, options)
+	default:
+		return decodeJson(path, data, jsonLocator(data), collector, options)
+	}
+}
+
+func decodeJson(path string, data []byte, locate diagnostics.Locator, collector *diagnostics.Collector, options decodeOptions) (*model.BoardsInfo, diagnostics.Locator, error) {
+	if options.validator != nil || options.aliases != nil || options.coercion.Enabled() {
+		// Malformed JSON is reported by the decoding below
+		if document, err := decodeDocument(data); err == nil {
+			if normalizedLocate, changed := normalizeDocument(path, document, options, locate, collector); changed {
+				// Boards are decoded from the normalized document, while findings keep pointing at the original keys
+				if data, err = json.Marshal(document); err != nil {
+					return nil, locate, err
+				}
+				locate = normalizedLocate
+			}
+
+			if options.validator != nil {
+				if err := reportViolations(path, options.validator.Validate(document), locate, collector); err != nil {
+					return nil, locate, err
+				}
+			}
+		}
+	}
+
+	var boardsList model.BoardsInfo
+	if err := boardsList.Decode(data, path, collector, locate); err != nil {
+		return nil, locate, locateJsonError(err, data, locate)
+	}
+	return &boardsList, locate, nil
+}
+
+// normalizeDocument renames the aliased keys and coerces the mistyped values of the board objects of a decoded JSON boards
+// list or single board object. The returned locator positions the renamed fields at the keys they were read from.
+func normalizeDocument(path string, document interface{}, options decodeOptions, locate diagnostics.Locator, collector *diagnostics.Collector) (diagnostics.Locator, bool) {
+	object, isObject := document.(map[string]interface{})
+	if !isObject {
+		return locate, false
+	}
+
+	// A single board object is also located under "/boards/0", like the boards it is decoded to
+	pointers := []string{"/boards/0"}
+	boards := []interface{}{object}
+	single := true
+	if list, isList := object["boards"].([]interface{}); isList && len(list) > 0 {
+		single = false
+		pointers = make([]string, len(list))
+		for i := range list {
+			pointers[i] = fmt.Sprintf("/boards/%d", i)
+		}
+		boards = list
+	}
+
+	report := newAliasReport()
+	coerced := make([][]model.CoercedValue, len(boards))
+	for i, board := range boards {
+		if board, isObject := board.(map[string]interface{}); isObject {
+			applied := options.aliases.Apply(board)
+			report.add(pointers[i], applied)
+			if single {
+				report.add("", applied)
+			}
+			coerced[i] = options.coercion.Apply(board)
+		}
+	}
+
+	changed := len(report.applied) > 0
+	if changed {
+		report.emit(path, locate.Of(report.first[report.applied[0]]), collector)
+		locate = renamedLocator(locate, report.renamed)
+	}
+	for i, values := range coerced {
+		reportCoercions(path, i, pointers[i], values, options.flagCoerced, locate, collector)
+		for _, value := range values {
+			changed = changed || !value.Kept
+		}
+	}
+	return locate, changed
+}
+
+func reportCoercions(path string, index int, pointer string, values []model.CoercedValue, flagCoerced bool, locate diagnostics.Locator, collector *diagnostics.Collector) {
+	for _, value := range values {
+		position := locate.Of(pointer + "/" + escapePointer(value.Field))
+		if value.Kept {
+			// Validation reports every mistyped value left in the extra entries
+			if !flagCoerced {
+				collector.WarnAt(diagnostics.CodeInvalidType, path, index, position, "Kept field '%v' as an extra entry, expected %v, got %v '%v' which can't be converted", value.Field, value.Expected, jsonTypeName(value.Value), value.Value)
+			}
+			continue
+		}
+		if value.Coerced == nil {
+			collector.WarnAt(diagnostics.CodeInvalidType, path, index, position, "Rejected field '%v', expected %v, got %v '%v'", value.Field, value.Expected, jsonTypeName(value.Value), value.Value)
+			continue
+		}
+		if flagCoerced {
+			collector.WarnAt(diagnostics.CodeInvalidType, path, index, position, "Field '%v' must be %v, got %v '%v' coerced to %v '%v'", value.Field, value.Expected, jsonTypeName(value.Value), value.Value, jsonTypeName(value.Coerced), value.Coerced)
+			continue
+		}
+		collector.InfoAt(diagnostics.CodeCoercion, path, index, position, "Coerced field '%v' from %v '%v' to %v '%v'", value.Field, jsonTypeName(value.Value), value.Value, jsonTypeName(value.Coerced), value.Coerced)
+	}
+}
+
+// decodeDocument keeps numbers as json.Number, so re-encoding a renamed document never alters them
+func decodeDocument(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid data after top-level value")
+	}
+	return document, nil
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// YAML documents are converted to JSON so both formats share the same parsing and validation rules,
+// positions are taken from the YAML nodes
+func decodeYaml(path string, data []byte, collector *diagnostics.Collector, options decodeOptions) (*model.BoardsInfo, diagnostics.Locator, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		err = fmt.Errorf("invalid YAML: %v", err.Error())
+		if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			err = &diagnostics.PositionError{Position: diagnostics.Position{Line: line}, Err: err}
+		}
+		return nil, nil, err
+	}
+
+	keepTimestamps(&node)
+	var document interface{}
+	if err := node.Decode(&document); err != nil {
+		return nil, nil, fmt.Errorf("invalid YAML: %v", err.Error())
+	}
+	locate := yamlLocator(&node)
+
+	jsonData, err := json.Marshal(toJsonCompatible(document))
+	if err != nil {
+		return nil, locate, fmt.Errorf("failed to convert YAML to JSON: %v", err.Error())
+	}
+
+	return decodeJson(path, jsonData, locate, collector, options)
+}
+
+func reportViolations(path string, violations []schema.Violation, locate diagnostics.Locator, collector *diagnostics.Collector) error {
+	for _, violation := range violations {
+		index := violation.BoardIndex()
+		if index < 0 {
+			index = diagnostics.NoIndex
+		}
+		collector.ErrorAt(diagnostics.CodeSchema, path, index, locate.Of(violation.Pointer), "Schema violation at %v", violation.String())
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("file does not match the boards schema, %v violation(s)", len(violations))
+	}
+	return nil
+}
+
+// keepTimestamps turns unquoted timestamps such as 2021-03-04 into strings, so they are kept as written
+// instead of being decoded as time.Time and re-encoded as "2021-03-04T00:00:00Z"
+func keepTimestamps(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!timestamp" {
+		node.Tag = "!!str"
+	}
+	for _, child := range node.Content {
+		keepTimestamps(child)
+	}
+}
+
+// yaml.v3 produces map[interface{}]interface{} for mappings with non-string keys, which JSON can't encode
+func toJsonCompatible(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			typed[key] = toJsonCompatible(item)
+		}
+		return typed
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			converted[fmt.Sprint(key)] = toJsonCompatible(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = toJsonCompatible(item)
+		}
+		return typed
+	default:
+		return value
+	}
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"boards-merger/internal/model"
+)
+
+// DiffBoards compares two merged catalogs, boards are matched on their vendor and name.
+// Added and changed boards follow the order of the after catalog, removed boards the order of the before one.
+func DiffBoards(before *model.BoardsInfo, after *model.BoardsInfo) model.BoardsDiff {
+	return DiffBoardsWithIdentity(before, after, nil)
+}
+
+// DiffBoardsWithIdentity compares two merged catalogs, boards are matched on the fields of the identity
+func DiffBoardsWithIdentity(before *model.BoardsInfo, after *model.BoardsInfo, identity model.IdentityKey) model.BoardsDiff {
+	oldBoards := make(boardRegistry, len(before.Boards))
+	for _, board := range before.Boards {
+		oldBoards[identity.Key(board)] = board
+	}
+
+	var diff model.BoardsDiff
+	newBoards := make(map[string]void, len(after.Boards))
+	for _, board := range after.Boards {
+		boardHash := identity.Key(board)
+		newBoards[boardHash] = void{}
+
+		oldBoard, exists := oldBoards[boardHash]
+		if !exists {
+			diff.Added = append(diff.Added, board)
+			continue
+		}
+
+		if fields := model.DiffFields(oldBoard, board); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, model.BoardChange{Board: identity.Label(board), Name: board.Name, Vendor: board.Vendor, Fields: fields})
+		}
+	}
+
+	for _, board := range before.Boards {
+		if _, exists := newBoards[identity.Key(board)]; !exists {
+			diff.Removed = append(diff.Removed, board)
+		}
+	}
+
+	return diff
+}
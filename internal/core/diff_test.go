@@ -0,0 +1,94 @@
+package core_test
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/model"
+	"boards-merger/internal/utils/logger"
+	"boards-merger/internal/utils/testutils"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiffBoards(t *testing.T) {
+	before := &model.BoardsInfo{Boards: []model.Board{
+		{Name: "Board1", Vendor: "VendorA", Core: "CoreX", HasWiFi: &testutils.BoolTrue},
+		{Name: "Board2", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"ram": 512.0, "flash": 4.0}},
+		{Name: "Board3", Vendor: "VendorB"},
+	}}
+	after := &model.BoardsInfo{Boards: []model.Board{
+		{Name: "Board1", Vendor: "VendorA", Core: "CoreY", HasWiFi: &testutils.BoolTrue},
+		{Name: "Board2", Vendor: "VendorA", HasWiFi: &testutils.BoolFalse, ExtraEntries: map[string]interface{}{"ram": 512.0}},
+		{Name: "Board4", Vendor: "VendorB"},
+	}}
+
+	diff := core.DiffBoards(before, after)
+	if len(diff.Added) != 1 || diff.Added[0].Name != "Board4" {
+		t.Fatalf("Unexpected added boards: %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "Board3" {
+		t.Fatalf("Unexpected removed boards: %v", diff.Removed)
+	}
+
+	expected := []model.BoardChange{
+		{Board: "VendorA::Board1", Name: "Board1", Vendor: "VendorA", Fields: []model.FieldChange{
+			{Field: "core", Old: "CoreX", New: "CoreY"},
+		}},
+		{Board: "VendorA::Board2", Name: "Board2", Vendor: "VendorA", Fields: []model.FieldChange{
+			{Field: "flash", Old: 4.0, New: nil},
+			{Field: "has_wifi", Old: nil, New: false},
+		}},
+	}
+	if !reflect.DeepEqual(diff.Changed, expected) {
+		t.Fatalf("Unexpected changed boards: got %v, expected %v", diff.Changed, expected)
+	}
+	if diff.Summary() != "1 added, 1 removed, 2 changed" {
+		t.Fatalf("Unexpected summary: %v", diff.Summary())
+	}
+
+	if diff := core.DiffBoards(after, after); !diff.Empty() {
+		t.Fatalf("Unexpected differences between identical catalogs: %v", diff)
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+	boardsDir := filepath.Join(dir, "boards")
+	if err := os.Mkdir(boardsDir, 0755); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+	testutils.WriteToFile(t, filepath.Join(boardsDir, "boards-1.json"), `{"boards": [{"name": "Board1", "vendor": "VendorA", "core": "CoreX"}]}`)
+	testutils.WriteToFile(t, filepath.Join(boardsDir, "boards-2.json"), `{"name": "Board2", "vendor": "VendorB"}`)
+
+	// A previously merged output, including per-field sources
+	outputPath := filepath.Join(dir, "merged.json")
+	testutils.WriteToFile(t, outputPath, `{
+		"boards": [
+			{"name": "Board1", "vendor": "VendorA", "core": "CoreX", "_sources": {"name": {"file": "boards-1.json", "index": 0}}},
+			{"name": "Board2", "vendor": "VendorB"}
+		],
+		"_metadata": {"unique_vendors": 2, "total_boards": 2}
+	}`)
+
+	fromDir, err := core.LoadCatalog(boardsDir, core.ReadOptions{}, core.MergeOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+	fromFile, err := core.LoadCatalog(outputPath, core.ReadOptions{}, core.MergeOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	if diff := core.DiffBoards(fromDir, fromFile); !diff.Empty() {
+		t.Fatalf("Unexpected differences between a directory and its merged output: %v", diff.Details())
+	}
+
+	if _, err := core.LoadCatalog(filepath.Join(dir, "missing"), core.ReadOptions{}, core.MergeOptions{}); !errors.Is(err, core.ErrInvalidPath) {
+		t.Fatalf("Unexpected err: got %v, expected %v", err, core.ErrInvalidPath)
+	}
+}
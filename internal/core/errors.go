@@ -0,0 +1,11 @@
+package core
+
+import "errors"
+
+// Errors returned by ReadDirectory & ProcessJsonFiles are wrapped around these, callers can match them with errors.Is
+var (
+	ErrInvalidPath  = errors.New("invalid path")
+	ErrNotDirectory = errors.New("not a directory")
+	ErrNoFiles      = errors.New("no board files found")
+	ErrNoBoards     = errors.New("no valid boards found")
+)
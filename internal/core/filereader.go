@@ -1,27 +1,51 @@
 package core
 
 import (
+	"boards-merger/internal/diagnostics"
 	"boards-merger/internal/utils/logger"
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+type ReadOptions struct {
+	Recursive bool
+	// MaxDepth limits recursive traversal, depth 0 refers to direct children
+	MaxDepth int
+	// Diagnostics collects skipped paths, it may be nil
+	Diagnostics *diagnostics.Collector
+}
+
 func ReadDirectory(dirPath string, recursive bool, maxDepth int) ([]string, error) {
-	var jsonFiles []string
+	return ReadDirectoryWithOptions(dirPath, ReadOptions{Recursive: recursive, MaxDepth: maxDepth})
+}
+
+func ReadDirectoryWithOptions(dirPath string, options ReadOptions) ([]string, error) {
+	return ReadDirectoryContext(context.Background(), dirPath, options)
+}
+
+// ReadDirectoryContext stops walking the directory as soon as ctx is cancelled
+func ReadDirectoryContext(ctx context.Context, dirPath string, options ReadOptions) ([]string, error) {
+	var boardFiles []string
+	recursive, maxDepth, collector := options.Recursive, options.MaxDepth, options.Diagnostics
 
 	absDir, err := filepath.Abs(dirPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get an absolute path for: %v", dirPath)
+		return nil, fmt.Errorf("%w, failed to get an absolute path for: %v", ErrInvalidPath, dirPath)
 	}
 
 	info, err := os.Stat(absDir)
-	if err != nil {
-		return nil, fmt.Errorf("invalid path: %v", dirPath)
+	if errors.Is(err, fs.ErrPermission) {
+		return nil, fmt.Errorf("%w: %v", fs.ErrPermission, dirPath)
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, dirPath)
 	}
 	if !info.IsDir() {
-		return nil, fmt.Errorf("not a directory: %v", dirPath)
+		return nil, fmt.Errorf("%w: %v", ErrNotDirectory, dirPath)
 	}
 
 	logger.Info("Reading directory: %v", absDir)
@@ -32,14 +56,22 @@ func ReadDirectory(dirPath string, recursive bool, maxDepth int) ([]string, erro
 
 	rootDepth := strings.Count(filepath.ToSlash(absDir), "/")
 	walkFunc := func(path string, d os.DirEntry, pathError error) error {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("reading directory %v cancelled: %w", dirPath, err)
+		}
+
 		if pathError != nil {
-			logger.Warn("Skipping path due to error: %v", path)
+			// The root directory itself must be readable, unreadable sub-paths are skipped
+			if path == absDir {
+				return fmt.Errorf("failed to read directory %v: %w", dirPath, pathError)
+			}
+			collector.Warn(diagnostics.CodeSkippedPath, path, diagnostics.NoIndex, "Skipping path due to error: %v", pathError.Error())
 			return nil
 		}
 
 		// Avoid recursive directory walking
 		if d.Type()&os.ModeSymlink != 0 {
-			logger.Warn("Skipping Symbolic link: %v", path)
+			collector.Warn(diagnostics.CodeSkippedPath, path, diagnostics.NoIndex, "Skipping Symbolic link")
 			return nil
 		}
 
@@ -59,10 +91,10 @@ func ReadDirectory(dirPath string, recursive bool, maxDepth int) ([]string, erro
 			}
 		}
 
-		// '*.json' match is case insensitive
-		if !d.IsDir() && strings.ToLower(filepath.Ext(d.Name())) == ".json" {
-			jsonFiles = append(jsonFiles, absPath)
-			logger.Info("Found JSON file: %v", absPath)
+		// Extension match ('*.json', '*.yaml', '*.yml', '*.csv', '*.tsv') is case insensitive
+		if !d.IsDir() && isSupportedFile(d.Name()) {
+			boardFiles = append(boardFiles, absPath)
+			logger.Info("Found board file: %v", absPath)
 		}
 
 		return nil
@@ -72,9 +104,9 @@ func ReadDirectory(dirPath string, recursive bool, maxDepth int) ([]string, erro
 		return nil, err
 	}
 
-	if len(jsonFiles) == 0 {
-		return nil, fmt.Errorf("no JSON files found in %v", dirPath)
+	if len(boardFiles) == 0 {
+		return nil, fmt.Errorf("%w in %v", ErrNoFiles, dirPath)
 	}
 
-	return jsonFiles, nil
+	return boardFiles, nil
 }
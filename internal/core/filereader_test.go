@@ -35,6 +35,35 @@ func TestReadDirectory(t *testing.T) {
 			expectedErr: false,
 			expectedLen: 3,
 		},
+		{
+			name: "Directory with YAML files",
+			setup: func(t *testing.T) (string, func()) {
+				dir := testutils.CreateTempDir(t)
+				testutils.CreateTempFile(t, dir, "boards-1.yaml")
+				testutils.CreateTempFile(t, dir, "boards-2.YML")
+				testutils.CreateTempFile(t, dir, "boards-3.json")
+				testutils.CreateTempFile(t, dir, "boards-4.yamll")
+				return dir, func() { os.RemoveAll(dir) }
+			},
+			recursive:   false,
+			maxDepth:    0,
+			expectedErr: false,
+			expectedLen: 3,
+		},
+		{
+			name: "Directory with CSV and TSV files",
+			setup: func(t *testing.T) (string, func()) {
+				dir := testutils.CreateTempDir(t)
+				testutils.CreateTempFile(t, dir, "boards-1.csv")
+				testutils.CreateTempFile(t, dir, "boards-2.TSV")
+				testutils.CreateTempFile(t, dir, "boards-3.xlsx")
+				return dir, func() { os.RemoveAll(dir) }
+			},
+			recursive:   false,
+			maxDepth:    0,
+			expectedErr: false,
+			expectedLen: 2,
+		},
 		{
 			name: "Empty directory",
 			setup: func(t *testing.T) (string, func()) {
@@ -182,11 +211,11 @@ func TestReadDirectory(t *testing.T) {
 				t.Fatalf("Unexpected JSON file list length: got %v, expected %v", len(jsonFileList), test.expectedLen)
 			}
 
-			for _, jsonFile := range jsonFileList {
-				jsonFile = strings.ToLower(jsonFile)
+			for _, boardFile := range jsonFileList {
+				ext := strings.ToLower(filepath.Ext(boardFile))
 
-				if !strings.HasSuffix(jsonFile, ".json") {
-					t.Errorf("Unexpected file: %v", jsonFile)
+				if ext != ".json" && ext != ".yaml" && ext != ".yml" && ext != ".csv" && ext != ".tsv" {
+					t.Errorf("Unexpected file: %v", boardFile)
 				}
 			}
 		})
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"boards-merger/internal/model"
+	"flag"
+)
+
+// MergeFlags are the command line flags of the merge options shared by the CLI subcommands and the web server
+type MergeFlags struct {
+	aliases         *bool
+	aliasFile       *string
+	aliasIgnoreCase *bool
+	coerce          *string
+	identity        *string
+	// Flags of the merge only, nil when only the decoding flags are registered
+	vendors         *string
+	similarity      *float64
+	mergeSimilarity *float64
+	equivalences    *string
+}
+
+// RegisterDecodeFlags registers the field aliases, type coercion and board identity flags, applied to each file
+// even when the files are not merged
+func RegisterDecodeFlags(flags *flag.FlagSet) *MergeFlags {
+	return &MergeFlags{
+		aliases:         flags.Bool("aliases", false, "Rename non-standard keys such as 'manufacturer' or 'mcu' to the board fields (default: disabled)"),
+		aliasFile:       flags.String("alias-file", "", "Path of a JSON file of extra field aliases grouped by field, e.g. '{\"vendor\": [\"make\"]}'"),
+		aliasIgnoreCase: flags.Bool("alias-ignore-case", false, "Match field aliases and board fields regardless of case, e.g. 'Manufacturer' or 'Name' (default: disabled)"),
+		coerce:          flags.String("coerce", string(model.NoCoercion), "Handling of mistyped core & has_wifi values: off (kept as extra properties), strict (rejected) or lenient (known values converted, e.g. 'yes' or 1)"),
+		identity:        flags.String("identity", model.DefaultIdentity.String(), "Comma separated fields identifying a board, matching its entries and sorting the output, e.g. 'vendor,name,revision'"),
+	}
+}
+
+// RegisterMergeFlags registers the decoding flags, and the vendor registry and near-duplicate boards flags
+func RegisterMergeFlags(flags *flag.FlagSet) *MergeFlags {
+	mergeFlags := RegisterDecodeFlags(flags)
+	mergeFlags.vendors = flags.String("vendors", "", "Path of a JSON vendor registry of canonical vendor names and their aliases, e.g. '{\"Espressif\": [\"Espressif Systems\"]}'")
+	mergeFlags.similarity = flags.Float64("similarity", 0, "Report boards of the same vendor whose names are at least this similar, from 0 to 1, e.g. 0.85 (default: disabled)")
+	mergeFlags.mergeSimilarity = flags.Float64("merge-similarity", 0, "Merge boards of the same vendor whose names are at least this similar, from 0 to 1 (default: disabled)")
+	mergeFlags.equivalences = flags.String("equivalences", "", "Path of a JSON file of equivalent board names to merge, grouped by vendor, e.g. '{\"Espressif\": [[\"ESP32-DevKitC\", \"ESP32 DevKitC V4\"]]}'")
+	return mergeFlags
+}
+
+// Options loads the files named by the parsed flags and builds the merge options, the other options are left to the caller
+func (mergeFlags *MergeFlags) Options() (MergeOptions, error) {
+	var options MergeOptions
+	var err error
+	if options.Aliases, err = LoadFieldAliases(*mergeFlags.aliasFile, *mergeFlags.aliases, *mergeFlags.aliasIgnoreCase); err != nil {
+		return MergeOptions{}, err
+	}
+	if options.Coercion, err = model.ParseCoercion(*mergeFlags.coerce); err != nil {
+		return MergeOptions{}, err
+	}
+	if options.Identity, err = model.ParseIdentityKey(*mergeFlags.identity); err != nil {
+		return MergeOptions{}, err
+	}
+
+	if mergeFlags.vendors == nil {
+		return options, nil
+	}
+
+	if len(*mergeFlags.vendors) > 0 {
+		if options.Vendors, err = LoadVendorRegistry(*mergeFlags.vendors); err != nil {
+			return MergeOptions{}, err
+		}
+	}
+
+	options.Similarity = SimilarityOptions{MinScore: *mergeFlags.similarity, MergeScore: *mergeFlags.mergeSimilarity}
+	if err := options.Similarity.Validate(); err != nil {
+		return MergeOptions{}, err
+	}
+	if len(*mergeFlags.equivalences) > 0 {
+		if options.Similarity.Equivalences, err = LoadBoardEquivalences(*mergeFlags.equivalences); err != nil {
+			return MergeOptions{}, err
+		}
+	}
+	return options, nil
+}
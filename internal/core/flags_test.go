@@ -0,0 +1,93 @@
+package core_test
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/model"
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestMergeFlags(t *testing.T) {
+	tests := []struct {
+		name             string
+		decodeOnly       bool
+		args             []string
+		expectedCoercion model.Coercion
+		expectedIdentity model.IdentityKey
+		expectedAliases  bool
+		expectedScore    float64
+		expectedErr      bool
+	}{
+		{
+			name:             "Defaults",
+			expectedCoercion: model.NoCoercion,
+			expectedIdentity: model.DefaultIdentity,
+		},
+		{
+			name:             "Every flag",
+			args:             []string{"-aliases", "-coerce", "lenient", "-identity", "vendor,name,revision", "-merge-similarity", "0.95"},
+			expectedCoercion: model.LenientCoercion,
+			expectedIdentity: model.IdentityKey{"vendor", "name", "revision"},
+			expectedAliases:  true,
+			expectedScore:    0.95,
+		},
+		{
+			name:        "Unknown coercion",
+			args:        []string{"-coerce", "loose"},
+			expectedErr: true,
+		},
+		{
+			name:        "Invalid similarity",
+			args:        []string{"-similarity", "2"},
+			expectedErr: true,
+		},
+		{
+			name:        "Missing vendor registry",
+			args:        []string{"-vendors", "missing.json"},
+			expectedErr: true,
+		},
+		{
+			name:        "Merge flags are not registered for decoding only",
+			decodeOnly:  true,
+			args:        []string{"-similarity", "0.85"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags.SetOutput(io.Discard)
+			var mergeFlags *core.MergeFlags
+			if test.decodeOnly {
+				mergeFlags = core.RegisterDecodeFlags(flags)
+			} else {
+				mergeFlags = core.RegisterMergeFlags(flags)
+			}
+
+			err := flags.Parse(test.args)
+			var options core.MergeOptions
+			if err == nil {
+				options, err = mergeFlags.Options()
+			}
+			if test.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			if options.Coercion != test.expectedCoercion || !reflect.DeepEqual(options.Identity, test.expectedIdentity) {
+				t.Fatalf("Unexpected options: %+v", options)
+			}
+			if (options.Aliases != nil) != test.expectedAliases || options.Similarity.MergeScore != test.expectedScore {
+				t.Fatalf("Unexpected options: %+v", options)
+			}
+		})
+	}
+}
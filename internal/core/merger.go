@@ -1,82 +1,207 @@
 package core
 
 import (
+	"boards-merger/internal/diagnostics"
 	"boards-merger/internal/model"
-	"boards-merger/internal/utils/logger"
-	"encoding/json"
+	"boards-merger/internal/schema"
+	"context"
 	"fmt"
-	"os"
-	"sort"
+	"strings"
+	"time"
 )
 
 type boardRegistry map[string]model.Board
 type void struct{}
-type stringSet map[string]void
 
-func hashBoard(vendor string, name string) string {
-	return fmt.Sprintf("%s::%s", vendor, name)
-}
-
-func sortedKeys(m boardRegistry) []string {
-	keys := make([]string, 0, len(m))
-	for key := range m {
-		keys = append(keys, key)
+// sortedBoards returns the boards of the registry in the given order
+func sortedBoards(m boardRegistry, order model.SortOrder, identity model.IdentityKey) []model.Board {
+	boards := make([]model.Board, 0, len(m))
+	for _, board := range m {
+		boards = append(boards, board)
 	}
 
-	sort.Strings(keys)
+	order.Sort(boards, identity)
 
-	return keys
+	return boards
+}
+
+type MergeOptions struct {
+	// Strategy resolves conflicting values of duplicate boards, the zero value keeps the latest value read
+	Strategy model.MergeStrategy
+	// Provenance keeps the per-field sources of every board, emitted under a '_sources' key
+	Provenance bool
+	// Diagnostics collects skipped files & boards, duplicates and conflicts, it may be nil
+	Diagnostics *diagnostics.Collector
+	// Workers is the number of files read and parsed concurrently, 0 uses one worker per CPU
+	Workers int
+	// Schema rejects files that do not match it before merging, it may be nil
+	Schema *schema.Validator
+	// Aliases renames non-standard keys (e.g. "manufacturer") to the board fields before validation, it may be nil
+	Aliases *model.FieldAliases
+	// Coercion converts or rejects mistyped values of the typed fields, the zero value keeps them as extra entries
+	Coercion model.Coercion
+	// Identity is the ordered list of fields matching the entries of a board and sorting the merged boards,
+	// the zero value matches boards on their vendor and name
+	Identity model.IdentityKey
+	// Sort orders the merged boards before their identity, the zero value sorts them by identity only
+	Sort model.SortOrder
+	// GroupByVendor nests the merged boards under their vendor in the JSON output
+	GroupByVendor bool
+	// ExtendedMetaData adds per-vendor, core & WiFi counts, file counts, merged duplicates, generation time, version and
+	// a content hash to the metadata
+	ExtendedMetaData bool
+	// Similarity reports and merges near-duplicate boards once merged, the zero value disables it
+	Similarity SimilarityOptions
+	// Vendors replaces vendor names by their canonical name before boards are matched, unknown vendors are reported.
+	// It may be nil.
+	Vendors *model.VendorRegistry
 }
 
 func ProcessJsonFiles(jsonFilePaths []string) (*model.BoardsInfo, error) {
+	return ProcessJsonFilesWithOptions(jsonFilePaths, MergeOptions{})
+}
+
+func ProcessJsonFilesWithOptions(jsonFilePaths []string, options MergeOptions) (*model.BoardsInfo, error) {
+	return ProcessJsonFilesContext(context.Background(), jsonFilePaths, options)
+}
+
+// ProcessJsonFilesContext stops reading and parsing files as soon as ctx is cancelled
+func ProcessJsonFilesContext(ctx context.Context, jsonFilePaths []string, options MergeOptions) (*model.BoardsInfo, error) {
 	var boardsMap = make(boardRegistry)
-	var vendorSet = make(stringSet)
 	var boardsInfo model.BoardsInfo
+	var conflicts = newConflictReport(nil)
+	var collector = options.Diagnostics
+	var vendors = newVendorReport()
+	var skippedFiles, mergedDuplicates int
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Files are parsed concurrently but merged in read order, so conflict resolution and findings stay deterministic
+	for parsed := range parseFiles(ctx, jsonFilePaths, options.Workers, decodeOptions{validator: options.Schema, aliases: options.Aliases, coercion: options.Coercion}) {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("merging boards cancelled: %w", err)
+		}
 
-	for _, path := range jsonFilePaths {
-		jsonFile, err := os.ReadFile(path)
-		if err != nil {
-			logger.Error("Failed to read the JSON file, skipping file '%v'", path)
-			fmt.Println(err.Error())
+		path := parsed.path
+		collector.Extend(parsed.findings)
+		if parsed.readErr != nil {
+			skippedFiles++
+			collector.Error(diagnostics.CodeReadError, path, diagnostics.NoIndex, "Failed to read the board file, skipping file: %v", parsed.readErr.Error())
 			continue
 		}
-		logger.Info("Parsing file: %v", path)
-
-		var boardsList model.BoardsInfo
-		if err := json.Unmarshal(jsonFile, &boardsList); err != nil {
-			logger.Error("%v, skipping file '%v'", err.Error(), path)
+		if parsed.parseErr != nil {
+			skippedFiles++
+			collector.ErrorAt(diagnostics.CodeParseError, path, diagnostics.NoIndex, diagnostics.PositionOf(parsed.parseErr), "%v, skipping file", parsed.parseErr.Error())
 			continue
 		}
 
-		for _, board := range boardsList.Boards {
-			boardHash := hashBoard(board.Vendor, board.Name)
+		for _, board := range parsed.boards.Boards {
+			if options.Vendors != nil {
+				vendors.canonicalize(options.Vendors, &board, path, parsed.locate)
+			}
+			boardHash := options.Identity.Key(board)
 
-			// Try to merge boards that has the same name and vendor, conflicting info resolution is based on read order
+			// Try to merge boards that has the same identity, conflicting info resolution is based on read order
 			if existingBoard, exists := boardsMap[boardHash]; exists {
-				logger.Warn("Found a duplicate entry for board '%v' made by '%v', Attempting to merge them", board.Name, board.Vendor)
-				board.Merge(existingBoard)
+				collector.Warn(diagnostics.CodeDuplicate, path, board.Sources["name"].Index, "Found a duplicate entry for board '%v' made by '%v', Attempting to merge them", board.Name, board.Vendor)
+				boardConflicts, err := existingBoard.MergeWith(board, options.Strategy)
+				if err != nil {
+					return nil, fmt.Errorf("failed to merge '%v': %w", path, err)
+				}
+				for _, conflict := range boardConflicts {
+					collector.Warn(diagnostics.CodeConflict, path, board.Sources["name"].Index, "Two entries for %v boards have conflicting info about '%v': {'%v', '%v'}, choosing '%v'",
+						board.Name, conflict.Field, conflict.Candidates[0].Value, conflict.Candidates[1].Value, conflict.Chosen)
+				}
+				conflicts.add(options.Identity.Label(board), boardConflicts)
+				mergedDuplicates++
+				board = existingBoard
 			}
 			boardsMap[boardHash] = board
-
-			vendorSet[board.Vendor] = void{}
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("merging boards cancelled: %w", err)
+	}
+	vendors.emit(collector)
 
 	if len(boardsMap) == 0 {
-		return nil, fmt.Errorf("no valid boards found")
+		return nil, ErrNoBoards
 	}
 
-	// Copy back boards into result object based on sorted board hash (vendor::name)
-	sortekBoardKeys := sortedKeys(boardsMap)
-	boardsInfo.Boards = make([]model.Board, 0, len(boardsMap))
-	for _, key := range sortekBoardKeys {
-		boardsInfo.Boards = append(boardsInfo.Boards, boardsMap[key])
-	}
+	// Copy back boards into result object in the sort order, then by identity (e.g. vendor, then name)
+	boardsInfo.Boards = sortedBoards(boardsMap, options.Sort, options.Identity)
+	boardsInfo.GroupByVendor = options.GroupByVendor
 
-	boardsInfo.MetaData = model.MetaData{
-		UniqueVendors: len(vendorSet),
-		TotalBoards:   len(boardsMap),
+	boardsInfo.UpdateMetaData()
+	boardsInfo.Conflicts = conflicts.list
+
+	// Near-duplicates are located with the sources of the boards, so they are compared before sources are dropped
+	if options.Similarity.enabled() {
+		boardsCount := len(boardsInfo.Boards)
+		fileOrder := make(map[string]int, len(jsonFilePaths))
+		for i, path := range jsonFilePaths {
+			fileOrder[path] = i
+		}
+		if err := mergeNearDuplicates(&boardsInfo, options, fileOrder); err != nil {
+			return nil, err
+		}
+		mergedDuplicates += boardsCount - len(boardsInfo.Boards)
+	}
+	if options.ExtendedMetaData {
+		boardsInfo.MetaData.ExtendedMetaData = &model.ExtendedMetaData{
+			Files:            model.FileCounts{Scanned: len(jsonFilePaths), Parsed: len(jsonFilePaths) - skippedFiles, Skipped: skippedFiles},
+			MergedDuplicates: mergedDuplicates,
+			GeneratedAt:      time.Now().UTC(),
+			ToolVersion:      Version,
+		}
+		boardsInfo.UpdateMetaData()
+	}
+	if !options.Provenance {
+		for i := range boardsInfo.Boards {
+			boardsInfo.Boards[i].Sources, boardsInfo.Boards[i].ExtraSources = nil, nil
+		}
 	}
 
 	return &boardsInfo, nil
 }
+
+// conflictReport groups the conflicts of a board field across all of its duplicate entries
+type conflictReport struct {
+	list  []model.Conflict
+	index map[string]int
+}
+
+// newConflictReport continues the given list of conflicts, which may be nil
+func newConflictReport(list []model.Conflict) *conflictReport {
+	report := &conflictReport{list: list, index: make(map[string]int)}
+	for i, conflict := range list {
+		report.index[conflictKey(conflict)] = i
+	}
+	return report
+}
+
+// add records the conflicts of a board, labelled as boardLabel in the report
+func (report *conflictReport) add(boardLabel string, conflicts []model.Conflict) {
+	for _, conflict := range conflicts {
+		conflict.Board = boardLabel
+		key := conflictKey(conflict)
+
+		i, exists := report.index[key]
+		if !exists {
+			report.index[key] = len(report.list)
+			report.list = append(report.list, conflict)
+			continue
+		}
+
+		// The current value of a repeated conflict is the previously chosen value, already listed as a candidate
+		existing := &report.list[i]
+		existing.Candidates = append(existing.Candidates, conflict.Candidates[len(conflict.Candidates)-1])
+		existing.Chosen = conflict.Chosen
+	}
+}
+
+// conflictKey groups the conflicts of a field of a board, the vendor and name are kept apart so labels such as "A::B::C" are never ambiguous
+func conflictKey(conflict model.Conflict) string {
+	return strings.Join([]string{conflict.Vendor, conflict.Name, conflict.Board, conflict.Field}, "\x00")
+}
@@ -2,11 +2,19 @@ package core_test
 
 import (
 	"boards-merger/internal/core"
+	"boards-merger/internal/diagnostics"
 	"boards-merger/internal/model"
+	"boards-merger/internal/schema"
 	"boards-merger/internal/utils/logger"
 	"boards-merger/internal/utils/testutils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -16,6 +24,7 @@ func TestProcessJsonFiles(t *testing.T) {
 	tests := []struct {
 		name               string
 		setup              func(t *testing.T) ([]string, func())
+		strategy           string
 		expectedErr        bool
 		expectedBoardsInfo *model.BoardsInfo
 	}{
@@ -93,6 +102,200 @@ func TestProcessJsonFiles(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Mixed JSON and YAML files",
+			setup: func(t *testing.T) ([]string, func()) {
+				dir := testutils.CreateTempDir(t)
+				filePath := filepath.Join(dir, "boards-1.yaml")
+				yamlContent := `boards:
+  - name: Board2
+    vendor: VendorB
+    has_wifi: true
+    extra_feature_1: "yes"
+  - name: Board1
+    vendor: VendorA
+    core: CoreX
+    pins: 40
+`
+				testutils.WriteToFile(t, filePath, yamlContent)
+
+				filePath2 := filepath.Join(dir, "boards-2.yml")
+				yamlContent2 := `name: Board3
+vendor: VendorC
+has_wifi: false
+`
+				testutils.WriteToFile(t, filePath2, yamlContent2)
+
+				filePath3 := filepath.Join(dir, "boards-3.json")
+				jsonContent3 := `{"name": "Board1", "vendor": "VendorA", "has_wifi": false, "extra_feature_2": "no"}`
+				testutils.WriteToFile(t, filePath3, jsonContent3)
+				return []string{filePath, filePath2, filePath3}, func() { os.RemoveAll(dir) }
+			},
+			expectedErr: false,
+			expectedBoardsInfo: &model.BoardsInfo{
+				Boards: []model.Board{
+					{
+						Name:    "Board1",
+						Vendor:  "VendorA",
+						Core:    "CoreX",
+						HasWiFi: &testutils.BoolFalse,
+						ExtraEntries: map[string]interface{}{
+							"pins":            float64(40),
+							"extra_feature_2": "no",
+						},
+					},
+					{
+						Name:    "Board2",
+						Vendor:  "VendorB",
+						HasWiFi: &testutils.BoolTrue,
+						ExtraEntries: map[string]interface{}{
+							"extra_feature_1": "yes",
+						},
+					},
+					{
+						Name:    "Board3",
+						Vendor:  "VendorC",
+						HasWiFi: &testutils.BoolFalse,
+					},
+				},
+				MetaData: model.MetaData{
+					UniqueVendors: 3,
+					TotalBoards:   3,
+				},
+			},
+		},
+		{
+			name: "YAML file with dates",
+			setup: func(t *testing.T) ([]string, func()) {
+				dir := testutils.CreateTempDir(t)
+				filePath := filepath.Join(dir, "boards-1.yaml")
+				yamlContent := "name: Board1\nvendor: VendorA\nreleased: 2021-03-04\nupdated: 2021-03-04T10:20:30Z\nrevisions: [2020-01-02]\n"
+				testutils.WriteToFile(t, filePath, yamlContent)
+				return []string{filePath}, func() { os.RemoveAll(dir) }
+			},
+			expectedErr: false,
+			expectedBoardsInfo: &model.BoardsInfo{
+				Boards: []model.Board{
+					{
+						Name:   "Board1",
+						Vendor: "VendorA",
+						ExtraEntries: map[string]interface{}{
+							"released":  "2021-03-04",
+							"updated":   "2021-03-04T10:20:30Z",
+							"revisions": []interface{}{"2020-01-02"},
+						},
+					},
+				},
+				MetaData: model.MetaData{
+					UniqueVendors: 1,
+					TotalBoards:   1,
+				},
+			},
+		},
+		{
+			name: "Invalid YAML",
+			setup: func(t *testing.T) ([]string, func()) {
+				dir := testutils.CreateTempDir(t)
+				filePath := filepath.Join(dir, "boards-1.yaml")
+				yamlContent := "boards: [\n  - name: Board1"
+				testutils.WriteToFile(t, filePath, yamlContent)
+				return []string{filePath}, func() { os.RemoveAll(dir) }
+			},
+			expectedErr:        true,
+			expectedBoardsInfo: nil,
+		},
+		{
+			name: "CSV and TSV files with type inference and malformed rows",
+			setup: func(t *testing.T) ([]string, func()) {
+				dir := testutils.CreateTempDir(t)
+				filePath := filepath.Join(dir, "boards-1.csv")
+				csvContent := "Name,Vendor,Core,Has WiFi,pins,sku,price\n" +
+					"Board1,VendorA,CoreX,true,40,00123,4.5\n" +
+					"Board2,VendorA,,FALSE,,,\n" +
+					",VendorA,CoreY,true,1,2,3\n" +
+					"Board3,VendorB,CoreZ\n"
+				testutils.WriteToFile(t, filePath, csvContent)
+
+				filePath2 := filepath.Join(dir, "boards-2.TSV")
+				tsvContent := "name\tvendor\tnotes\n" +
+					"Board4\tVendorB\tfirst revision\n"
+				testutils.WriteToFile(t, filePath2, tsvContent)
+				return []string{filePath, filePath2}, func() { os.RemoveAll(dir) }
+			},
+			expectedErr: false,
+			expectedBoardsInfo: &model.BoardsInfo{
+				Boards: []model.Board{
+					{
+						Name:    "Board1",
+						Vendor:  "VendorA",
+						Core:    "CoreX",
+						HasWiFi: &testutils.BoolTrue,
+						ExtraEntries: map[string]interface{}{
+							"pins":  float64(40),
+							"sku":   "00123",
+							"price": 4.5,
+						},
+					},
+					{
+						Name:    "Board2",
+						Vendor:  "VendorA",
+						HasWiFi: &testutils.BoolFalse,
+					},
+					{
+						Name:   "Board4",
+						Vendor: "VendorB",
+						ExtraEntries: map[string]interface{}{
+							"notes": "first revision",
+						},
+					},
+				},
+				MetaData: model.MetaData{
+					UniqueVendors: 2,
+					TotalBoards:   3,
+				},
+			},
+		},
+		{
+			name: "CSV file with numeric name, vendor and core",
+			setup: func(t *testing.T) ([]string, func()) {
+				dir := testutils.CreateTempDir(t)
+				filePath := filepath.Join(dir, "boards-1.csv")
+				csvContent := "name,vendor,core,has_wifi,pins\n" +
+					"8266,3Com,8051,true,40\n"
+				testutils.WriteToFile(t, filePath, csvContent)
+				return []string{filePath}, func() { os.RemoveAll(dir) }
+			},
+			expectedErr: false,
+			expectedBoardsInfo: &model.BoardsInfo{
+				Boards: []model.Board{
+					{
+						Name:    "8266",
+						Vendor:  "3Com",
+						Core:    "8051",
+						HasWiFi: &testutils.BoolTrue,
+						ExtraEntries: map[string]interface{}{
+							"pins": float64(40),
+						},
+					},
+				},
+				MetaData: model.MetaData{
+					UniqueVendors: 1,
+					TotalBoards:   1,
+				},
+			},
+		},
+		{
+			name: "CSV file without required columns",
+			setup: func(t *testing.T) ([]string, func()) {
+				dir := testutils.CreateTempDir(t)
+				filePath := filepath.Join(dir, "boards-1.csv")
+				csvContent := "name,core\nBoard1,CoreX\n"
+				testutils.WriteToFile(t, filePath, csvContent)
+				return []string{filePath}, func() { os.RemoveAll(dir) }
+			},
+			expectedErr:        true,
+			expectedBoardsInfo: nil,
+		},
 		{
 			name: "Empty Boards",
 			setup: func(t *testing.T) ([]string, func()) {
@@ -212,6 +415,72 @@ func TestProcessJsonFiles(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Duplicate boards with first-wins strategy",
+			setup: func(t *testing.T) ([]string, func()) {
+				dir := testutils.CreateTempDir(t)
+				filePath := filepath.Join(dir, "boards-1.json")
+				jsonContent := `{
+                    "boards": [
+						{"name": "Board1", "vendor": "VendorA", "core": "CoreY", "has_wifi": false, "extra_feature_1": "yes"}
+                    ]
+                }`
+				testutils.WriteToFile(t, filePath, jsonContent)
+				filePath2 := filepath.Join(dir, "boards-2.json")
+				jsonContent2 := `{
+                    "boards": [
+						{"name": "Board1", "vendor": "VendorA", "core": "CoreX", "has_wifi": false, "extra_feature_1": "no"}
+                    ]
+                }`
+				testutils.WriteToFile(t, filePath2, jsonContent2)
+
+				return []string{filePath, filePath2}, func() { os.RemoveAll(dir) }
+			},
+			strategy:    "first-wins",
+			expectedErr: false,
+			expectedBoardsInfo: &model.BoardsInfo{
+				Boards: []model.Board{
+					{
+						Name:    "Board1",
+						Vendor:  "VendorA",
+						Core:    "CoreY",
+						HasWiFi: &testutils.BoolFalse,
+						ExtraEntries: map[string]interface{}{
+							"extra_feature_1": "yes",
+						},
+					},
+				},
+				MetaData: model.MetaData{
+					UniqueVendors: 1,
+					TotalBoards:   1,
+				},
+			},
+		},
+		{
+			name: "Duplicate boards with fail-on-conflict strategy",
+			setup: func(t *testing.T) ([]string, func()) {
+				dir := testutils.CreateTempDir(t)
+				filePath := filepath.Join(dir, "boards-1.json")
+				jsonContent := `{
+                    "boards": [
+						{"name": "Board1", "vendor": "VendorA", "core": "CoreY", "has_wifi": false, "extra_feature_1": "yes"}
+                    ]
+                }`
+				testutils.WriteToFile(t, filePath, jsonContent)
+				filePath2 := filepath.Join(dir, "boards-2.json")
+				jsonContent2 := `{
+                    "boards": [
+						{"name": "Board1", "vendor": "VendorA", "core": "CoreX", "has_wifi": false, "extra_feature_1": "no"}
+                    ]
+                }`
+				testutils.WriteToFile(t, filePath2, jsonContent2)
+
+				return []string{filePath, filePath2}, func() { os.RemoveAll(dir) }
+			},
+			strategy:           "last-wins,extra_feature_1=fail-on-conflict",
+			expectedErr:        true,
+			expectedBoardsInfo: nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -219,7 +488,12 @@ func TestProcessJsonFiles(t *testing.T) {
 			paths, cleanup := test.setup(t)
 			defer cleanup()
 
-			actualBoardsInfo, err := core.ProcessJsonFiles(paths)
+			strategy, err := model.ParseMergeStrategy(test.strategy)
+			if err != nil {
+				t.Fatalf("unexpected strategy error: %v", err)
+			}
+
+			actualBoardsInfo, err := core.ProcessJsonFilesWithOptions(paths, core.MergeOptions{Strategy: strategy})
 
 			if (err != nil) != test.expectedErr {
 				t.Fatalf("unexpected error status: got %v, expected error: %v", err, test.expectedErr)
@@ -229,3 +503,672 @@ func TestProcessJsonFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessJsonFilesConflicts(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var paths []string
+	for i, content := range []string{
+		`{"boards": [{"name": "Board2", "vendor": "VendorB"}, {"name": "Board1", "vendor": "VendorA", "core": "CoreX"}]}`,
+		`{"name": "Board1", "vendor": "VendorA", "core": "CoreY", "has_wifi": true}`,
+		`{"name": "Board1", "vendor": "VendorA", "core": "CoreZ", "has_wifi": true}`,
+	} {
+		path := filepath.Join(dir, fmt.Sprintf("boards-%v.json", i+1))
+		testutils.WriteToFile(t, path, content)
+		paths = append(paths, path)
+	}
+
+	boardsInfo, err := core.ProcessJsonFiles(paths)
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	expectedConflicts := []model.Conflict{
+		{
+			Board:  "VendorA::Board1",
+			Name:   "Board1",
+			Vendor: "VendorA",
+			Field:  "core",
+			Candidates: []model.Candidate{
+				{Value: "CoreX", Source: model.Source{File: paths[0], Index: 1}},
+				{Value: "CoreY", Source: model.Source{File: paths[1], Index: 0}},
+				{Value: "CoreZ", Source: model.Source{File: paths[2], Index: 0}},
+			},
+			Chosen: "CoreZ",
+		},
+	}
+	if !reflect.DeepEqual(boardsInfo.Conflicts, expectedConflicts) {
+		t.Fatalf("Unexpected conflicts: got %v, expected %v", boardsInfo.Conflicts, expectedConflicts)
+	}
+}
+
+func TestProcessJsonFilesProvenance(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "Board2", "vendor": "VendorB"}, {"name": "Board1", "vendor": "VendorA", "core": "CoreX"}]}`)
+	filePath2 := filepath.Join(dir, "boards-2.json")
+	testutils.WriteToFile(t, filePath2, `{"name": "Board1", "vendor": "VendorA", "has_wifi": true, "extra_feature_1": "yes"}`)
+
+	tests := []struct {
+		name                 string
+		provenance           bool
+		expectedSources      map[string]model.Source
+		expectedExtraSources map[string]model.Source
+	}{
+		{
+			name:            "Provenance disabled",
+			provenance:      false,
+			expectedSources: nil,
+		},
+		{
+			name:       "Provenance enabled",
+			provenance: true,
+			expectedSources: map[string]model.Source{
+				"name":     {File: filePath, Index: 1},
+				"vendor":   {File: filePath, Index: 1},
+				"core":     {File: filePath, Index: 1},
+				"has_wifi": {File: filePath2, Index: 0},
+			},
+			expectedExtraSources: map[string]model.Source{
+				"extra_feature_1": {File: filePath2, Index: 0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			boardsInfo, err := core.ProcessJsonFilesWithOptions([]string{filePath, filePath2}, core.MergeOptions{Provenance: test.provenance})
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			if !reflect.DeepEqual(boardsInfo.Boards[0].Sources, test.expectedSources) {
+				t.Fatalf("Unexpected sources: got %v, expected %v", boardsInfo.Boards[0].Sources, test.expectedSources)
+			}
+			if !reflect.DeepEqual(boardsInfo.Boards[0].ExtraSources, test.expectedExtraSources) {
+				t.Fatalf("Unexpected extra sources: got %v, expected %v", boardsInfo.Boards[0].ExtraSources, test.expectedExtraSources)
+			}
+
+			data, err := json.Marshal(boardsInfo.Boards[0])
+			if err != nil {
+				t.Fatalf("Unexpected marshalling err: %v", err.Error())
+			}
+			if strings.Contains(string(data), `"_sources"`) != test.provenance {
+				t.Fatalf("Unexpected '_sources' key presence in %s", data)
+			}
+		})
+	}
+}
+
+func TestProcessJsonFilesProvenanceMistypedField(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	// The mistyped values are kept as extra entries, next to the typed value of the CSV file
+	filePath := filepath.Join(dir, "a.yaml")
+	testutils.WriteToFile(t, filePath, "name: Board1\nvendor: VendorA\nhas_wifi: \"yes\"\n")
+	filePath2 := filepath.Join(dir, "b.csv")
+	testutils.WriteToFile(t, filePath2, "name,vendor,has_wifi\nBoard1,VendorA,true\n")
+	filePath3 := filepath.Join(dir, "c.json")
+	testutils.WriteToFile(t, filePath3, `{"name": "Board1", "vendor": "VendorA", "has_wifi": "no"}`)
+
+	boardsInfo, err := core.ProcessJsonFilesWithOptions([]string{filePath, filePath2, filePath3}, core.MergeOptions{Provenance: true})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	board := boardsInfo.Boards[0]
+	if board.Sources["has_wifi"] != (model.Source{File: filePath2, Index: 0}) {
+		t.Fatalf("Unexpected has_wifi source: %v", board.Sources)
+	}
+	if board.ExtraSources["has_wifi"] != (model.Source{File: filePath3, Index: 0}) {
+		t.Fatalf("Unexpected extra has_wifi source: %v", board.ExtraSources)
+	}
+
+	expectedCandidates := []model.Candidate{
+		{Value: "yes", Source: model.Source{File: filePath, Index: 0}},
+		{Value: "no", Source: model.Source{File: filePath3, Index: 0}},
+	}
+	if len(boardsInfo.Conflicts) != 1 || !reflect.DeepEqual(boardsInfo.Conflicts[0].Candidates, expectedCandidates) {
+		t.Fatalf("Unexpected conflicts: got %v, expected candidates %v", boardsInfo.Conflicts, expectedCandidates)
+	}
+}
+
+func TestProcessJsonFilesDiagnostics(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "Board1", "vendor": "VendorA"}, {"vendor": "VendorA"}]}`)
+	filePath2 := filepath.Join(dir, "boards-2.json")
+	testutils.WriteToFile(t, filePath2, `}`)
+	filePath3 := filepath.Join(dir, "boards-3.json")
+
+	collector := diagnostics.NewCollector()
+	_, err := core.ProcessJsonFilesWithOptions([]string{filePath, filePath2, filePath3}, core.MergeOptions{Diagnostics: collector})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	expected := []diagnostics.Diagnostic{
+		{Severity: diagnostics.Warning, Code: diagnostics.CodeInvalidBoard, File: filePath, Index: 1, Position: diagnostics.Position{Line: 1, Column: 54}},
+		{Severity: diagnostics.Error, Code: diagnostics.CodeParseError, File: filePath2, Index: diagnostics.NoIndex, Position: diagnostics.Position{Line: 1, Column: 1}},
+		{Severity: diagnostics.Error, Code: diagnostics.CodeReadError, File: filePath3, Index: diagnostics.NoIndex},
+	}
+	findings := collector.Diagnostics(diagnostics.Info)
+	if len(findings) != len(expected) {
+		t.Fatalf("Unexpected findings: got %v, expected %v", findings, expected)
+	}
+	for i := range expected {
+		findings[i].Message = ""
+		if findings[i] != expected[i] {
+			t.Fatalf("Unexpected finding at %v: got %v, expected %v", i, findings[i], expected[i])
+		}
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"name": "Board1", "vendor": "VendorA"}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := core.ReadDirectoryContext(ctx, dir, core.ReadOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Unexpected read err: got %v, expected %v", err, context.Canceled)
+	}
+	if _, err := core.ProcessJsonFilesContext(ctx, []string{filePath}, core.MergeOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Unexpected merge err: got %v, expected %v", err, context.Canceled)
+	}
+}
+
+// writeBenchmarkFiles writes board list files, every tenth board of a file is shared by all files with conflicting values
+func writeBenchmarkFiles(t testing.TB, dir string, files int, boardsPerFile int) []string {
+	t.Helper()
+
+	paths := make([]string, 0, files)
+	for i := 0; i < files; i++ {
+		var builder strings.Builder
+		builder.WriteString(`{"boards": [`)
+		for j := 0; j < boardsPerFile; j++ {
+			if j > 0 {
+				builder.WriteString(",")
+			}
+			name := fmt.Sprintf("Board%d-%d", i, j)
+			if j%10 == 0 {
+				name = fmt.Sprintf("Board%d", j)
+			}
+			fmt.Fprintf(&builder, `{"name": "%s", "vendor": "Vendor%d", "core": "Core%d", "has_wifi": %v, "ram": %d}`, name, j%7, i%3, j%20 == 0, i)
+		}
+		builder.WriteString("]}")
+
+		path := filepath.Join(dir, fmt.Sprintf("boards-%04d.json", i))
+		testutils.WriteToFile(t, path, builder.String())
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestProcessJsonFilesWorkers(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	paths := writeBenchmarkFiles(t, dir, 40, 20)
+	testutils.WriteToFile(t, filepath.Join(dir, "boards-invalid.json"), `}`)
+	paths = append(paths[:10], append([]string{filepath.Join(dir, "boards-invalid.json"), filepath.Join(dir, "missing.json")}, paths[10:]...)...)
+
+	merge := func(workers int) ([]byte, []diagnostics.Diagnostic) {
+		collector := diagnostics.NewCollector()
+		boards, err := core.ProcessJsonFilesWithOptions(paths, core.MergeOptions{Provenance: true, Diagnostics: collector, Workers: workers})
+		if err != nil {
+			t.Fatalf("Unexpected err with %v workers: %v", workers, err.Error())
+		}
+		data, err := json.Marshal(boards)
+		if err != nil {
+			t.Fatalf("Unexpected marshal err: %v", err.Error())
+		}
+		return data, collector.Diagnostics(diagnostics.Info)
+	}
+
+	expectedData, expectedFindings := merge(1)
+	for _, workers := range []int{0, 2, 8} {
+		data, findings := merge(workers)
+		if string(data) != string(expectedData) {
+			t.Fatalf("Unexpected result with %v workers: got %v, expected %v", workers, string(data), string(expectedData))
+		}
+		if !reflect.DeepEqual(findings, expectedFindings) {
+			t.Fatalf("Unexpected findings with %v workers: got %v, expected %v", workers, findings, expectedFindings)
+		}
+	}
+}
+
+func BenchmarkProcessJsonFiles(b *testing.B) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(b)
+	defer os.RemoveAll(dir)
+	paths := writeBenchmarkFiles(b, dir, 200, 100)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := core.ProcessJsonFilesWithOptions(paths, core.MergeOptions{Workers: workers}); err != nil {
+					b.Fatalf("Unexpected err: %v", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestProcessJsonFilesSchema(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "Board1", "vendor": "VendorA"}, {"name": "Board2", "vendor": "VendorA", "has_wifi": "yes"}]}`)
+	filePath2 := filepath.Join(dir, "boards-2.csv")
+	testutils.WriteToFile(t, filePath2, "name,vendor,has_wifi\nBoard3,VendorB,true\n")
+
+	validator, err := schema.New("")
+	if err != nil {
+		t.Fatalf("Unexpected schema err: %v", err.Error())
+	}
+
+	collector := diagnostics.NewCollector()
+	boards, err := core.ProcessJsonFilesWithOptions([]string{filePath, filePath2}, core.MergeOptions{Schema: validator, Diagnostics: collector})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	// The whole file with a violation is skipped
+	if len(boards.Boards) != 1 || boards.Boards[0].Name != "Board3" {
+		t.Fatalf("Unexpected boards: %v", boards.Boards)
+	}
+
+	expected := []diagnostics.Diagnostic{
+		{Severity: diagnostics.Error, Code: diagnostics.CodeSchema, File: filePath, Index: 1, Position: diagnostics.Position{Line: 1, Column: 106}, Message: "Schema violation at /boards/1/has_wifi: expected boolean, but got string"},
+		{Severity: diagnostics.Error, Code: diagnostics.CodeParseError, File: filePath, Index: diagnostics.NoIndex, Message: "file does not match the boards schema, 1 violation(s), skipping file"},
+	}
+	if findings := collector.Diagnostics(diagnostics.Info); !reflect.DeepEqual(findings, expected) {
+		t.Fatalf("Unexpected findings: got %v, expected %v", findings, expected)
+	}
+}
+
+func TestProcessJsonFilesAliases(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"board_name": "Board1", "manufacturer": "VendorA", "mcu": "Cortex-M4"}, {"name": "Board2", "vendor": "VendorA", "maker": "VendorZ"}]}`)
+	filePath2 := filepath.Join(dir, "boards-2.yaml")
+	testutils.WriteToFile(t, filePath2, "boards:\n  - Name: Board3\n    Brand: VendorB\n    WiFi: true\n")
+	filePath3 := filepath.Join(dir, "boards-3.csv")
+	testutils.WriteToFile(t, filePath3, "Board Name,Manufacturer,CPU\nBoard4,VendorC,Xtensa\n")
+
+	validator, err := schema.New("")
+	if err != nil {
+		t.Fatalf("Unexpected schema err: %v", err.Error())
+	}
+	aliases, err := core.LoadFieldAliases("", true, true)
+	if err != nil {
+		t.Fatalf("Unexpected aliases err: %v", err.Error())
+	}
+
+	collector := diagnostics.NewCollector()
+	boards, err := core.ProcessJsonFilesWithOptions([]string{filePath, filePath2, filePath3}, core.MergeOptions{Schema: validator, Aliases: aliases, Diagnostics: collector})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	hasWiFi := true
+	expectedBoards := []model.Board{
+		{Name: "Board1", Vendor: "VendorA", Core: "Cortex-M4", ExtraEntries: map[string]interface{}{}},
+		// The exact field key takes precedence over its alias
+		{Name: "Board2", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"maker": "VendorZ"}},
+		{Name: "Board3", Vendor: "VendorB", HasWiFi: &hasWiFi, ExtraEntries: map[string]interface{}{}},
+		{Name: "Board4", Vendor: "VendorC", Core: "Xtensa", ExtraEntries: map[string]interface{}{}},
+	}
+	if !reflect.DeepEqual(boards.Boards, expectedBoards) {
+		t.Fatalf("Unexpected boards: got %v, expected %v", boards.Boards, expectedBoards)
+	}
+
+	expected := []diagnostics.Diagnostic{
+		{Severity: diagnostics.Info, Code: diagnostics.CodeFieldAlias, File: filePath, Index: diagnostics.NoIndex, Position: diagnostics.Position{Line: 1, Column: 28}, Message: "Mapped field aliases: 'board_name' to 'name', 'manufacturer' to 'vendor', 'mcu' to 'core'"},
+		{Severity: diagnostics.Info, Code: diagnostics.CodeFieldAlias, File: filePath2, Index: diagnostics.NoIndex, Position: diagnostics.Position{Line: 3, Column: 12}, Message: "Mapped field aliases: 'Brand' to 'vendor', 'Name' to 'name', 'WiFi' to 'has_wifi'"},
+		{Severity: diagnostics.Info, Code: diagnostics.CodeFieldAlias, File: filePath3, Index: diagnostics.NoIndex, Position: diagnostics.Position{Line: 1, Column: 1}, Message: "Mapped field aliases: 'Board Name' to 'name', 'Manufacturer' to 'vendor', 'CPU' to 'core'"},
+	}
+	if findings := collector.Diagnostics(diagnostics.Info); !reflect.DeepEqual(findings, expected) {
+		t.Fatalf("Unexpected findings: got %v, expected %v", findings, expected)
+	}
+}
+
+func TestProcessJsonFilesCoercion(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "Board1", "vendor": "VendorA", "has_wifi": "yes", "core": 8051}, {"name": "Board2", "vendor": "VendorA", "has_wifi": "maybe"}]}`)
+	filePath2 := filepath.Join(dir, "boards-2.csv")
+	testutils.WriteToFile(t, filePath2, "name,vendor,has_wifi\nBoard3,VendorB,N\n")
+
+	tests := []struct {
+		name             string
+		coercion         model.Coercion
+		expectedBoards   []model.Board
+		expectedFindings []diagnostics.Diagnostic
+	}{
+		{
+			name:     "Off",
+			coercion: model.NoCoercion,
+			expectedBoards: []model.Board{
+				{Name: "Board1", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"has_wifi": "yes", "core": 8051.0}},
+				{Name: "Board2", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"has_wifi": "maybe"}},
+				{Name: "Board3", Vendor: "VendorB", ExtraEntries: map[string]interface{}{"has_wifi": "N"}},
+			},
+		},
+		{
+			name:     "Lenient",
+			coercion: model.LenientCoercion,
+			expectedBoards: []model.Board{
+				{Name: "Board1", Vendor: "VendorA", Core: "8051", HasWiFi: &testutils.BoolTrue, ExtraEntries: map[string]interface{}{}},
+				{Name: "Board2", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"has_wifi": "maybe"}},
+				{Name: "Board3", Vendor: "VendorB", HasWiFi: &testutils.BoolFalse, ExtraEntries: map[string]interface{}{}},
+			},
+			expectedFindings: []diagnostics.Diagnostic{
+				{Severity: diagnostics.Info, Code: diagnostics.CodeCoercion, File: filePath, Index: 0, Position: diagnostics.Position{Line: 1, Column: 80}, Message: "Coerced field 'core' from number '8051' to string '8051'"},
+				{Severity: diagnostics.Info, Code: diagnostics.CodeCoercion, File: filePath, Index: 0, Position: diagnostics.Position{Line: 1, Column: 65}, Message: "Coerced field 'has_wifi' from string 'yes' to boolean 'true'"},
+				{Severity: diagnostics.Warning, Code: diagnostics.CodeInvalidType, File: filePath, Index: 1, Position: diagnostics.Position{Line: 1, Column: 139}, Message: "Kept field 'has_wifi' as an extra entry, expected a boolean, got string 'maybe' which can't be converted"},
+				{Severity: diagnostics.Info, Code: diagnostics.CodeCoercion, File: filePath2, Index: 0, Position: diagnostics.Position{Line: 2, Column: 16}, Message: "Coerced field 'has_wifi' from string 'N' to boolean 'false'"},
+			},
+		},
+		{
+			name:     "Strict",
+			coercion: model.StrictCoercion,
+			expectedBoards: []model.Board{
+				{Name: "Board1", Vendor: "VendorA", ExtraEntries: map[string]interface{}{}},
+				{Name: "Board2", Vendor: "VendorA", ExtraEntries: map[string]interface{}{}},
+				{Name: "Board3", Vendor: "VendorB", ExtraEntries: map[string]interface{}{}},
+			},
+			expectedFindings: []diagnostics.Diagnostic{
+				{Severity: diagnostics.Warning, Code: diagnostics.CodeInvalidType, File: filePath, Index: 0, Position: diagnostics.Position{Line: 1, Column: 80}, Message: "Rejected field 'core', expected a string, got number '8051'"},
+				{Severity: diagnostics.Warning, Code: diagnostics.CodeInvalidType, File: filePath, Index: 0, Position: diagnostics.Position{Line: 1, Column: 65}, Message: "Rejected field 'has_wifi', expected a boolean, got string 'yes'"},
+				{Severity: diagnostics.Warning, Code: diagnostics.CodeInvalidType, File: filePath, Index: 1, Position: diagnostics.Position{Line: 1, Column: 139}, Message: "Rejected field 'has_wifi', expected a boolean, got string 'maybe'"},
+				{Severity: diagnostics.Warning, Code: diagnostics.CodeInvalidType, File: filePath2, Index: 0, Position: diagnostics.Position{Line: 2, Column: 16}, Message: "Rejected field 'has_wifi', expected a boolean, got string 'N'"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collector := diagnostics.NewCollector()
+			boards, err := core.ProcessJsonFilesWithOptions([]string{filePath, filePath2}, core.MergeOptions{Coercion: test.coercion, Diagnostics: collector})
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			if !reflect.DeepEqual(boards.Boards, test.expectedBoards) {
+				t.Fatalf("Unexpected boards: got %v, expected %v", boards.Boards, test.expectedBoards)
+			}
+			if findings := collector.Diagnostics(diagnostics.Info); !reflect.DeepEqual(findings, test.expectedFindings) {
+				t.Fatalf("Unexpected findings: got %v, expected %v", findings, test.expectedFindings)
+			}
+		})
+	}
+}
+
+func TestProcessJsonFilesVendors(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "Board1", "vendor": "Espressif Systems", "core": "Xtensa"}, {"name": "Board2", "vendor": "Nordic"}]}`)
+	filePath2 := filepath.Join(dir, "boards-2.json")
+	testutils.WriteToFile(t, filePath2, `{"boards": [{"name": "Board1", "vendor": "espressif", "has_wifi": true}, {"name": "Board3", "vendor": "Nordic"}]}`)
+
+	vendorsPath := filepath.Join(dir, "vendors.json")
+	testutils.WriteToFile(t, vendorsPath, `{"Espressif": ["Espressif Systems"]}`)
+	registry, err := core.LoadVendorRegistry(vendorsPath)
+	if err != nil {
+		t.Fatalf("Unexpected registry err: %v", err.Error())
+	}
+
+	collector := diagnostics.NewCollector()
+	boards, err := core.ProcessJsonFilesWithOptions([]string{filePath, filePath2}, core.MergeOptions{Vendors: registry, Diagnostics: collector})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	expectedBoards := []model.Board{
+		{Name: "Board1", Vendor: "Espressif", Core: "Xtensa", HasWiFi: &testutils.BoolTrue, ExtraEntries: map[string]interface{}{}},
+		{Name: "Board2", Vendor: "Nordic", ExtraEntries: map[string]interface{}{}},
+		{Name: "Board3", Vendor: "Nordic", ExtraEntries: map[string]interface{}{}},
+	}
+	if !reflect.DeepEqual(boards.Boards, expectedBoards) {
+		t.Fatalf("Unexpected boards: got %v, expected %v", boards.Boards, expectedBoards)
+	}
+	if boards.MetaData.UniqueVendors != 2 {
+		t.Fatalf("Unexpected unique vendors: %v", boards.MetaData.UniqueVendors)
+	}
+
+	findings := collector.Diagnostics(diagnostics.Warning)
+	expected := []diagnostics.Diagnostic{
+		{Severity: diagnostics.Warning, Code: diagnostics.CodeDuplicate, File: filePath2, Index: 0, Message: "Found a duplicate entry for board 'Board1' made by 'Espressif', Attempting to merge them"},
+		{Severity: diagnostics.Warning, Code: diagnostics.CodeUnknownVendor, File: filePath, Index: 1, Position: diagnostics.Position{Line: 1, Column: 111}, Message: "Vendor 'Nordic' of 2 board(s) does not match any vendor registry entry"},
+	}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Fatalf("Unexpected findings: got %v, expected %v", findings, expected)
+	}
+}
+
+func TestProcessJsonFilesIdentity(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "Board1", "vendor": "VendorB", "revision": "B", "core": "CoreY"}, {"name": "Board1", "vendor": "VendorB", "revision": "A", "core": "CoreX"}, {"name": "Board2", "vendor": "VendorA"}]}`)
+	filePath2 := filepath.Join(dir, "boards-2.json")
+	testutils.WriteToFile(t, filePath2, `{"boards": [{"name": "Board1", "vendor": "VendorB", "revision": "A", "has_wifi": true}]}`)
+
+	tests := []struct {
+		name           string
+		identity       string
+		expectedBoards []string
+	}{
+		{name: "Default identity", identity: "vendor,name", expectedBoards: []string{"VendorA::Board2", "VendorB::Board1"}},
+		{name: "Revisions stay separate", identity: "vendor,name,revision", expectedBoards: []string{"VendorA::Board2::", "VendorB::Board1::A", "VendorB::Board1::B"}},
+		{name: "Sorted by name first", identity: "name,revision,vendor", expectedBoards: []string{"Board1::A::VendorB", "Board1::B::VendorB", "Board2::::VendorA"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity, err := model.ParseIdentityKey(test.identity)
+			if err != nil {
+				t.Fatalf("Unexpected identity err: %v", err.Error())
+			}
+
+			boards, err := core.ProcessJsonFilesWithOptions([]string{filePath, filePath2}, core.MergeOptions{Identity: identity})
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			var keys []string
+			for _, board := range boards.Boards {
+				keys = append(keys, identity.Label(board))
+			}
+			if !reflect.DeepEqual(keys, test.expectedBoards) {
+				t.Fatalf("Unexpected boards: got %v, expected %v", keys, test.expectedBoards)
+			}
+			if boards.MetaData.TotalBoards != len(test.expectedBoards) {
+				t.Fatalf("Unexpected metadata: %v", boards.MetaData)
+			}
+		})
+	}
+}
+
+func TestProcessJsonFilesSortAndGroup(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [
+		{"name": "Board10", "vendor": "vendorB"},
+		{"name": "Board9", "vendor": "VendorA::Lab"},
+		{"name": "Board2", "vendor": "vendorB"},
+		{"name": "Board1", "vendor": "VendorA", "revision": 3},
+		{"name": "Board3", "vendor": "VendorA", "revision": 12}
+	]}`)
+
+	tests := []struct {
+		name           string
+		sort           string
+		expectedBoards []string
+	}{
+		{name: "Default order", sort: "", expectedBoards: []string{"VendorA/Board1", "VendorA/Board3", "VendorA::Lab/Board9", "vendorB/Board10", "vendorB/Board2"}},
+		{name: "Natural order", sort: "vendor,name", expectedBoards: []string{"VendorA/Board1", "VendorA/Board3", "VendorA::Lab/Board9", "vendorB/Board2", "vendorB/Board10"}},
+		{name: "Extra property descending", sort: "revision:desc", expectedBoards: []string{"VendorA/Board3", "VendorA/Board1", "VendorA::Lab/Board9", "vendorB/Board10", "vendorB/Board2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, err := model.ParseSortOrder(test.sort)
+			if err != nil {
+				t.Fatalf("Unexpected sort err: %v", err.Error())
+			}
+
+			boards, err := core.ProcessJsonFilesWithOptions([]string{filePath}, core.MergeOptions{Sort: order, GroupByVendor: true})
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			var names []string
+			for _, board := range boards.Boards {
+				names = append(names, board.Vendor+"/"+board.Name)
+			}
+			if !reflect.DeepEqual(names, test.expectedBoards) {
+				t.Fatalf("Unexpected order: got %v, expected %v", names, test.expectedBoards)
+			}
+
+			// The grouped output is read back as the same catalog
+			data, err := json.Marshal(boards)
+			if err != nil {
+				t.Fatalf("Unexpected marshal err: %v", err.Error())
+			}
+			if !strings.HasPrefix(string(data), `{"vendors":[{"vendor":`) {
+				t.Fatalf("Unexpected grouped output: %s", data)
+			}
+			outputPath := filepath.Join(dir, "merged.json")
+			testutils.WriteToFile(t, outputPath, string(data))
+			catalog, err := core.LoadCatalog(outputPath, core.ReadOptions{}, core.MergeOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected catalog err: %v", err.Error())
+			}
+			if diff := core.DiffBoards(boards, catalog); !diff.Empty() {
+				t.Fatalf("Unexpected differences with the grouped output: %v", diff.Details())
+			}
+		})
+	}
+}
+
+func TestProcessJsonFilesExtendedMetaData(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "Board1", "vendor": "VendorA", "core": "CoreX"}, {"name": "ESP32-DevKitC", "vendor": "VendorB", "has_wifi": true}]}`)
+	filePath2 := filepath.Join(dir, "boards-2.json")
+	testutils.WriteToFile(t, filePath2, `{"boards": [{"name": "Board1", "vendor": "VendorA", "has_wifi": false}, {"name": "ESP32 DevKitC", "vendor": "VendorB"}]}`)
+	invalidPath := filepath.Join(dir, "invalid.json")
+	testutils.WriteToFile(t, invalidPath, `{"boards": [`)
+	files := []string{filePath, filePath2, invalidPath, filepath.Join(dir, "missing.json")}
+
+	boards, err := core.ProcessJsonFilesWithOptions(files, core.MergeOptions{ExtendedMetaData: true, Similarity: core.SimilarityOptions{MergeScore: 0.95}})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	extended := boards.MetaData.ExtendedMetaData
+	if extended == nil {
+		t.Fatalf("Missing extended metadata")
+	}
+	if extended.Files != (model.FileCounts{Scanned: 4, Parsed: 2, Skipped: 2}) {
+		t.Fatalf("Unexpected file counts: %+v", extended.Files)
+	}
+	// Board1 is a duplicate entry, and the second ESP32 DevKitC a near-duplicate
+	if extended.MergedDuplicates != 2 {
+		t.Fatalf("Unexpected merged duplicates: %v", extended.MergedDuplicates)
+	}
+	if !reflect.DeepEqual(extended.BoardsPerVendor, map[string]int{"VendorA": 1, "VendorB": 1}) || extended.WiFi != (model.WiFiCounts{With: 1, Without: 1}) {
+		t.Fatalf("Unexpected board statistics: %+v", extended)
+	}
+	if extended.ToolVersion != core.Version || extended.GeneratedAt.IsZero() {
+		t.Fatalf("Unexpected generation info: %v, %v", extended.ToolVersion, extended.GeneratedAt)
+	}
+
+	// The merged output holds the same boards, hence the same content hash
+	data, err := json.Marshal(boards)
+	if err != nil {
+		t.Fatalf("Unexpected marshal err: %v", err.Error())
+	}
+	outputPath := filepath.Join(dir, "merged.json")
+	testutils.WriteToFile(t, outputPath, string(data))
+	catalog, err := core.LoadCatalog(outputPath, core.ReadOptions{}, core.MergeOptions{ExtendedMetaData: true})
+	if err != nil {
+		t.Fatalf("Unexpected catalog err: %v", err.Error())
+	}
+	if catalog.MetaData.ContentHash != extended.ContentHash {
+		t.Fatalf("Unexpected content hash of the merged output: got %v, expected %v", catalog.MetaData.ContentHash, extended.ContentHash)
+	}
+}
+
+func TestProcessJsonFilesIdentitySeparator(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "C", "vendor": "A::B", "core": "CoreX"}, {"name": "B::C", "vendor": "A", "core": "CoreY"}]}`)
+
+	boards, err := core.ProcessJsonFiles([]string{filePath})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+	if len(boards.Boards) != 2 || len(boards.Conflicts) != 0 {
+		t.Fatalf("Unexpected boards: %+v", boards)
+	}
+	if boards.Boards[0].Vendor != "A" || boards.Boards[1].Vendor != "A::B" {
+		t.Fatalf("Unexpected order: %+v", boards.Boards)
+	}
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"boards-merger/internal/diagnostics"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mapLocator resolves pointers from a position index. A pointer without a position falls back to its closest parent,
+// and pointers into "/boards/0" also match a file holding a single board object.
+func mapLocator(positions map[string]diagnostics.Position) diagnostics.Locator {
+	return func(pointer string) diagnostics.Position {
+		for {
+			if position, exists := positions[pointer]; exists {
+				return position
+			}
+			if rest, found := strings.CutPrefix(pointer, "/boards/0"); found && (len(rest) == 0 || rest[0] == '/') {
+				if position, exists := positions[rest]; exists {
+					return position
+				}
+			}
+			if len(pointer) == 0 {
+				return diagnostics.Position{}
+			}
+			pointer = pointer[:strings.LastIndex(pointer, "/")]
+		}
+	}
+}
+
+// jsonLocator indexes the positions of every JSON value on first use, files without findings never pay for it
+func jsonLocator(data []byte) diagnostics.Locator {
+	var once sync.Once
+	var locate diagnostics.Locator
+	return func(pointer string) diagnostics.Position {
+		once.Do(func() {
+			locate = mapLocator(jsonPositions(data))
+		})
+		return locate(pointer)
+	}
+}
+
+func jsonPositions(data []byte) map[string]diagnostics.Position {
+	type container struct {
+		pointer   string
+		isArray   bool
+		index     int
+		key       string
+		expectKey bool
+	}
+
+	positions := make(map[string]diagnostics.Position)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var stack []*container
+	for {
+		start := skipSeparators(data, int(decoder.InputOffset()))
+		token, err := decoder.Token()
+		if err != nil {
+			return positions
+		}
+
+		var parent *container
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+
+		if delim, isDelim := token.(json.Delim); isDelim && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if parent != nil && parent.expectKey {
+			parent.key, parent.expectKey = token.(string), false
+			continue
+		}
+
+		pointer := ""
+		if parent != nil {
+			if parent.isArray {
+				pointer = parent.pointer + "/" + strconv.Itoa(parent.index)
+				parent.index++
+			} else {
+				pointer = parent.pointer + "/" + escapePointer(parent.key)
+				parent.expectKey = true
+			}
+		}
+		positions[pointer] = offsetPosition(data, start)
+
+		if delim, isDelim := token.(json.Delim); isDelim {
+			stack = append(stack, &container{pointer: pointer, isArray: delim == '[', expectKey: delim == '{'})
+		}
+	}
+}
+
+// skipSeparators moves past the whitespace, colons and commas the decoder offset may point at
+func skipSeparators(data []byte, offset int) int {
+	for offset < len(data) && strings.IndexByte(" \t\r\n:,", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func offsetPosition(data []byte, offset int) diagnostics.Position {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	return diagnostics.Position{Line: line, Column: utf8.RuneCount(data[lineStart:offset]) + 1}
+}
+
+// yamlLocator indexes the positions of the values of a parsed YAML document
+func yamlLocator(document *yaml.Node) diagnostics.Locator {
+	positions := make(map[string]diagnostics.Position)
+	var walk func(node *yaml.Node, pointer string)
+	walk = func(node *yaml.Node, pointer string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, pointer)
+			}
+			return
+		case yaml.AliasNode:
+			return
+		}
+
+		positions[pointer] = diagnostics.Position{Line: node.Line, Column: node.Column}
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1], pointer+"/"+escapePointer(node.Content[i].Value))
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				walk(child, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	walk(document, "")
+	return mapLocator(positions)
+}
+
+// locateJsonError positions syntax errors by their offset, and type errors by the pointer of the mistyped field
+func locateJsonError(err error, data []byte, locate diagnostics.Locator) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// The offending character is the last one read
+		return &diagnostics.PositionError{Position: offsetPosition(data, max(int(syntaxErr.Offset)-1, 0)), Err: err}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && len(typeErr.Field) > 0 {
+		pointer := "/" + strings.ReplaceAll(typeErr.Field, ".", "/")
+		return &diagnostics.PositionError{Position: locate.Of(pointer), Err: err}
+	}
+
+	return err
+}
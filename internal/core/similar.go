@@ -0,0 +1,209 @@
+package core
+
+import (
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// SimilarityOptions configure the detection of near-duplicate boards, the zero value disables it
+type SimilarityOptions struct {
+	// MinScore is the lowest name similarity (0 to 1) of a reported pair of boards, 0 reports only merged pairs
+	MinScore float64
+	// MergeScore merges the pairs of boards whose names are at least this similar, 0 disables it
+	MergeScore float64
+	// Equivalences merges the boards listed as equivalent whatever their similarity, it may be nil
+	Equivalences *model.BoardEquivalences
+}
+
+func (options SimilarityOptions) enabled() bool {
+	return options.MinScore > 0 || options.MergeScore > 0 || options.Equivalences != nil
+}
+
+func (options SimilarityOptions) Validate() error {
+	if options.MinScore < 0 || options.MinScore > 1 || options.MergeScore < 0 || options.MergeScore > 1 {
+		return fmt.Errorf("similarity scores must be between 0 and 1")
+	}
+	return nil
+}
+
+// LoadBoardEquivalences reads a JSON file of equivalent board names grouped by vendor,
+// e.g. {"Espressif": [["ESP32-DevKitC", "ESP32 DevKitC V4"]]}
+func LoadBoardEquivalences(path string) (*model.BoardEquivalences, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read board equivalences: %v", err.Error())
+	}
+
+	var vendors map[string][][]string
+	if err := json.Unmarshal(data, &vendors); err != nil {
+		return nil, fmt.Errorf("invalid board equivalences %v: %v", path, err.Error())
+	}
+	return model.NewBoardEquivalences(vendors)
+}
+
+// MergeNearDuplicates compares the names of the merged boards sharing the other fields of their identity (e.g. their vendor),
+// pairs scoring at least MinScore are reported as probable duplicates, and pairs scoring at least MergeScore or listed
+// as equivalent are merged with the strategy of the options, in the order their entries were read.
+// Merged pairs are grouped transitively: A and C are merged through B when both score above MergeScore with B,
+// whatever the score of A and C.
+// The pairs and the conflicts of merged boards are added to boardsInfo, and its metadata is recomputed.
+func MergeNearDuplicates(boardsInfo *model.BoardsInfo, mergeOptions MergeOptions) error {
+	return mergeNearDuplicates(boardsInfo, mergeOptions, nil)
+}
+
+// mergeNearDuplicates orders the files of the board sources by fileOrder, files missing from it are ordered by path
+func mergeNearDuplicates(boardsInfo *model.BoardsInfo, mergeOptions MergeOptions, fileOrder map[string]int) error {
+	options, strategy, identity, collector := mergeOptions.Similarity, mergeOptions.Strategy, mergeOptions.Identity, mergeOptions.Diagnostics
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	boards := boardsInfo.Boards
+	groups := newBoardGroups(len(boards))
+
+	// Boards are compared within groups of the same identity but for their name
+	comparedBoards := make(map[string][]int)
+	var compared []string
+	for i, board := range boards {
+		board.Name = ""
+		key := identity.Key(board)
+		if _, exists := comparedBoards[key]; !exists {
+			compared = append(compared, key)
+		}
+		comparedBoards[key] = append(comparedBoards[key], i)
+	}
+
+	var pairs []model.NearDuplicate
+	for _, key := range compared {
+		indexes := comparedBoards[key]
+		for a, i := range indexes {
+			vendor := boards[i].Vendor
+			for _, j := range indexes[a+1:] {
+				score := model.NameSimilarity(boards[i].Name, boards[j].Name)
+				canonicalI, listedI := options.Equivalences.Canonical(vendor, boards[i].Name)
+				canonicalJ, listedJ := options.Equivalences.Canonical(vendor, boards[j].Name)
+				merged := (listedI && listedJ && canonicalI == canonicalJ) || (options.MergeScore > 0 && score >= options.MergeScore)
+				if !merged && (options.MinScore == 0 || score < options.MinScore) {
+					continue
+				}
+
+				pair := model.NearDuplicate{Vendor: vendor, Names: [2]string{boards[i].Name, boards[j].Name}, Score: score, Merged: merged}
+				source := boards[j].Sources["name"]
+				if merged {
+					groups.union(i, j)
+					collector.Info(diagnostics.CodeNearDuplicate, source.File, source.Index, "Merging near-duplicate boards '%v' and '%v' made by '%v' (similarity %v)", pair.Names[0], pair.Names[1], vendor, score)
+				} else {
+					collector.Warn(diagnostics.CodeNearDuplicate, source.File, source.Index, "Boards '%v' and '%v' made by '%v' are probable duplicates (similarity %v)", pair.Names[0], pair.Names[1], vendor, score)
+				}
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+
+	// Each group is merged in read order into the board read first, named after its equivalence group when listed,
+	// so the strategy resolves conflicts the same way as for duplicate entries
+	conflicts := newConflictReport(boardsInfo.Conflicts)
+	boardsMap := make(boardRegistry)
+	members := groups.members()
+	for root := range boards {
+		if groups.find(root) != root {
+			continue
+		}
+
+		group := members[root]
+		sort.SliceStable(group, func(i int, j int) bool {
+			return sourceBefore(firstSource(boards[group[i]], fileOrder), firstSource(boards[group[j]], fileOrder), fileOrder)
+		})
+
+		kept := boards[group[0]]
+		for _, member := range group {
+			if canonical, listed := options.Equivalences.Canonical(kept.Vendor, boards[member].Name); listed {
+				kept.Name = canonical
+				break
+			}
+		}
+
+		boardHash := identity.Key(kept)
+		for _, member := range group[1:] {
+			other := boards[member]
+			other.Name = kept.Name
+			boardConflicts, err := kept.MergeWith(other, strategy)
+			if err != nil {
+				return fmt.Errorf("failed to merge near-duplicate boards: %w", err)
+			}
+			conflicts.add(identity.Label(kept), boardConflicts)
+		}
+		boardsMap[boardHash] = kept
+	}
+
+	boardsInfo.Boards = sortedBoards(boardsMap, mergeOptions.Sort, identity)
+	boardsInfo.Conflicts = conflicts.list
+	boardsInfo.NearDuplicates = pairs
+	boardsInfo.UpdateMetaData()
+	return nil
+}
+
+// firstSource is the earliest entry of a merged board, from which its fields were first read
+func firstSource(board model.Board, fileOrder map[string]int) model.Source {
+	var first model.Source
+	found := false
+	for _, source := range board.Sources {
+		if !found || sourceBefore(source, first, fileOrder) {
+			first, found = source, true
+		}
+	}
+	return first
+}
+
+func sourceBefore(a model.Source, b model.Source, fileOrder map[string]int) bool {
+	if a.File != b.File {
+		orderA, knownA := fileOrder[a.File]
+		orderB, knownB := fileOrder[b.File]
+		if knownA && knownB {
+			return orderA < orderB
+		}
+		return a.File < b.File
+	}
+	return a.Index < b.Index
+}
+
+// boardGroups is a union-find of board indexes, the root of a group is always its lowest index
+type boardGroups []int
+
+func newBoardGroups(size int) boardGroups {
+	groups := make(boardGroups, size)
+	for i := range groups {
+		groups[i] = i
+	}
+	return groups
+}
+
+func (groups boardGroups) find(i int) int {
+	for groups[i] != i {
+		groups[i] = groups[groups[i]]
+		i = groups[i]
+	}
+	return i
+}
+
+func (groups boardGroups) union(i int, j int) {
+	rootI, rootJ := groups.find(i), groups.find(j)
+	if rootI > rootJ {
+		rootI, rootJ = rootJ, rootI
+	}
+	groups[rootJ] = rootI
+}
+
+// members returns the indexes of every group by root, in increasing order
+func (groups boardGroups) members() map[int][]int {
+	members := make(map[int][]int)
+	for i := range groups {
+		root := groups.find(i)
+		members[root] = append(members[root], i)
+	}
+	return members
+}
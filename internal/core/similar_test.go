@@ -0,0 +1,177 @@
+package core_test
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"boards-merger/internal/utils/logger"
+	"boards-merger/internal/utils/testutils"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProcessJsonFilesNearDuplicates(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [
+		{"name": "ESP32-DevKitC", "vendor": "Espressif", "core": "Xtensa"},
+		{"name": "ESP32 DevKitC", "vendor": "Espressif", "has_wifi": true},
+		{"name": "ESP32-DevKitC-1", "vendor": "Espressif", "core": "Xtensa LX6"},
+		{"name": "ESP32-DevKitC", "vendor": "Other"}
+	]}`)
+
+	equivalences, err := model.NewBoardEquivalences(map[string][][]string{"Espressif": {{"ESP32-DevKitC V4", "ESP32-DevKitC-1", "ESP32 DevKitC"}}})
+	if err != nil {
+		t.Fatalf("Unexpected equivalences err: %v", err.Error())
+	}
+
+	tests := []struct {
+		name           string
+		similarity     core.SimilarityOptions
+		expectedNames  []string
+		expectedPairs  []model.NearDuplicate
+		expectedCounts map[diagnostics.Severity]int
+	}{
+		{
+			name:          "Report only",
+			similarity:    core.SimilarityOptions{MinScore: 0.9},
+			expectedNames: []string{"ESP32 DevKitC", "ESP32-DevKitC", "ESP32-DevKitC-1", "ESP32-DevKitC"},
+			expectedPairs: []model.NearDuplicate{
+				{Vendor: "Espressif", Names: [2]string{"ESP32 DevKitC", "ESP32-DevKitC"}, Score: 1},
+				{Vendor: "Espressif", Names: [2]string{"ESP32 DevKitC", "ESP32-DevKitC-1"}, Score: 0.92},
+				{Vendor: "Espressif", Names: [2]string{"ESP32-DevKitC", "ESP32-DevKitC-1"}, Score: 0.92},
+			},
+			expectedCounts: map[diagnostics.Severity]int{diagnostics.Warning: 3},
+		},
+		{
+			name:       "Merge above threshold",
+			similarity: core.SimilarityOptions{MinScore: 0.9, MergeScore: 1},
+			// The pair is merged into the board read first
+			expectedNames: []string{"ESP32-DevKitC", "ESP32-DevKitC-1", "ESP32-DevKitC"},
+			expectedPairs: []model.NearDuplicate{
+				{Vendor: "Espressif", Names: [2]string{"ESP32 DevKitC", "ESP32-DevKitC"}, Score: 1, Merged: true},
+				{Vendor: "Espressif", Names: [2]string{"ESP32 DevKitC", "ESP32-DevKitC-1"}, Score: 0.92},
+				{Vendor: "Espressif", Names: [2]string{"ESP32-DevKitC", "ESP32-DevKitC-1"}, Score: 0.92},
+			},
+			expectedCounts: map[diagnostics.Severity]int{diagnostics.Info: 1, diagnostics.Warning: 2},
+		},
+		{
+			name:       "Merge equivalent boards",
+			similarity: core.SimilarityOptions{Equivalences: equivalences},
+			// "ESP32-DevKitC" is listed too, since equivalent names match regardless of punctuation
+			expectedNames: []string{"ESP32-DevKitC V4", "ESP32-DevKitC"},
+			expectedPairs: []model.NearDuplicate{
+				{Vendor: "Espressif", Names: [2]string{"ESP32 DevKitC", "ESP32-DevKitC"}, Score: 1, Merged: true},
+				{Vendor: "Espressif", Names: [2]string{"ESP32 DevKitC", "ESP32-DevKitC-1"}, Score: 0.92, Merged: true},
+				{Vendor: "Espressif", Names: [2]string{"ESP32-DevKitC", "ESP32-DevKitC-1"}, Score: 0.92, Merged: true},
+			},
+			expectedCounts: map[diagnostics.Severity]int{diagnostics.Info: 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collector := diagnostics.NewCollector()
+			boards, err := core.ProcessJsonFilesWithOptions([]string{filePath}, core.MergeOptions{Similarity: test.similarity, Diagnostics: collector})
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			var names []string
+			for _, board := range boards.Boards {
+				names = append(names, board.Name)
+			}
+			if !reflect.DeepEqual(names, test.expectedNames) {
+				t.Fatalf("Unexpected boards: got %v, expected %v", names, test.expectedNames)
+			}
+			if !reflect.DeepEqual(boards.NearDuplicates, test.expectedPairs) {
+				t.Fatalf("Unexpected near-duplicates: got %v, expected %v", boards.NearDuplicates, test.expectedPairs)
+			}
+			if boards.MetaData.TotalBoards != len(test.expectedNames) {
+				t.Fatalf("Unexpected metadata: %v", boards.MetaData)
+			}
+
+			counts := make(map[diagnostics.Severity]int)
+			for _, finding := range collector.Diagnostics(diagnostics.Info) {
+				if finding.Code == diagnostics.CodeNearDuplicate {
+					counts[finding.Severity]++
+				}
+			}
+			if !reflect.DeepEqual(counts, test.expectedCounts) {
+				t.Fatalf("Unexpected findings: got %v, expected %v", counts, test.expectedCounts)
+			}
+		})
+	}
+}
+
+func TestProcessJsonFilesNearDuplicatesReadOrder(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	// The second file is read first, and its board sorts after the board of the first file
+	firstPath := filepath.Join(dir, "boards-2.json")
+	testutils.WriteToFile(t, firstPath, `{"boards": [{"name": "Nucleo-F401RE", "vendor": "ST", "core": "Cortex-M4"}]}`)
+	secondPath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, secondPath, `{"boards": [{"name": "Nucleo F401RE", "vendor": "ST", "core": "Cortex-M4F"}]}`)
+
+	tests := []struct {
+		name         string
+		strategy     model.Resolution
+		expectedCore string
+	}{
+		{name: "First wins keeps the board read first", strategy: model.FirstWins, expectedCore: "Cortex-M4"},
+		{name: "Last wins keeps the board read last", strategy: model.LastWins, expectedCore: "Cortex-M4F"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := core.MergeOptions{
+				Strategy:   model.MergeStrategy{Default: test.strategy},
+				Similarity: core.SimilarityOptions{MergeScore: 0.95},
+			}
+			boards, err := core.ProcessJsonFilesWithOptions([]string{firstPath, secondPath}, options)
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			if len(boards.Boards) != 1 || boards.Boards[0].Name != "Nucleo-F401RE" || boards.Boards[0].Core != test.expectedCore {
+				t.Fatalf("Unexpected boards: %v", boards.Boards)
+			}
+		})
+	}
+}
+
+func TestMergeNearDuplicatesConflicts(t *testing.T) {
+	logger.Disable()
+
+	boards := &model.BoardsInfo{Boards: []model.Board{
+		{Name: "Nucleo-F401RE", Vendor: "ST", Core: "Cortex-M4"},
+		{Name: "Nucleo F401RE", Vendor: "ST", Core: "Cortex-M4F"},
+	}}
+
+	strategy := model.MergeStrategy{Default: model.FirstWins}
+	if err := core.MergeNearDuplicates(boards, core.MergeOptions{Strategy: strategy, Similarity: core.SimilarityOptions{MergeScore: 0.95}}); err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	// Boards without sources are merged into the first one, in the order of the boards list
+	if len(boards.Boards) != 1 || boards.Boards[0].Name != "Nucleo-F401RE" || boards.Boards[0].Core != "Cortex-M4" {
+		t.Fatalf("Unexpected boards: %v", boards.Boards)
+	}
+	if len(boards.Conflicts) != 1 || boards.Conflicts[0].Board != "ST::Nucleo-F401RE" || boards.Conflicts[0].Field != "core" {
+		t.Fatalf("Unexpected conflicts: %v", boards.Conflicts)
+	}
+
+	strategy = model.MergeStrategy{Default: model.FailOnConflict}
+	if err := core.MergeNearDuplicates(boards, core.MergeOptions{Strategy: strategy, Similarity: core.SimilarityOptions{MergeScore: 1.5}}); err == nil {
+		t.Fatalf("Expected an error for an invalid score")
+	}
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"boards-merger/internal/schema"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// validationSeverities are the severities of the findings making a board or a file unusable, which are only
+// warnings when merging, since the other boards are still merged
+var validationSeverities = map[string]diagnostics.Severity{
+	diagnostics.CodeReadError:    diagnostics.Error,
+	diagnostics.CodeParseError:   diagnostics.Error,
+	diagnostics.CodeInvalidBoard: diagnostics.Error,
+	diagnostics.CodeSchema:       diagnostics.Error,
+}
+
+type ValidateOptions struct {
+	// Schema reports the violations of every file, it may be nil
+	Schema *schema.Validator
+	// Aliases renames non-standard keys to the board fields before validation, it may be nil
+	Aliases *model.FieldAliases
+	// Coercion converts or rejects mistyped values of the typed fields, the zero value keeps them as extra entries
+	Coercion model.Coercion
+	// Identity matches the boards defined more than once within a file, the zero value matches them on their vendor and name
+	Identity model.IdentityKey
+	// Diagnostics collects every finding, it may be nil
+	Diagnostics *diagnostics.Collector
+}
+
+// ValidateFiles checks the board files without merging them. On top of the findings of reading and parsing,
+// it reports boards defined more than once within a file and optional fields of the wrong type, including the coerced ones.
+// Unreadable files and boards, and schema violations, are reported as errors.
+func ValidateFiles(ctx context.Context, paths []string, options ValidateOptions) error {
+	collector := options.Diagnostics
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer collector.Override(validationSeverities)
+
+	for parsed := range parseFiles(ctx, paths, 0, decodeOptions{validator: options.Schema, aliases: options.Aliases, coercion: options.Coercion, flagCoerced: true}) {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("validating boards cancelled: %w", err)
+		}
+
+		path := parsed.path
+		collector.Extend(parsed.findings)
+		if parsed.readErr != nil {
+			collector.Error(diagnostics.CodeReadError, path, diagnostics.NoIndex, "Failed to read the board file: %v", parsed.readErr.Error())
+			continue
+		}
+		if parsed.parseErr != nil {
+			collector.ErrorAt(diagnostics.CodeParseError, path, diagnostics.NoIndex, diagnostics.PositionOf(parsed.parseErr), "%v", parsed.parseErr.Error())
+			continue
+		}
+
+		firstIndex := make(map[string]int)
+		for _, board := range parsed.boards.Boards {
+			index := board.Sources["name"].Index
+			boardHash := options.Identity.Key(board)
+			if first, exists := firstIndex[boardHash]; exists {
+				collector.WarnAt(diagnostics.CodeDuplicateInFile, path, index, parsed.locate.Of(fmt.Sprintf("/boards/%d", index)), "Board '%v' made by '%v' is already defined at index %v of the same file", board.Name, board.Vendor, first)
+			} else {
+				firstIndex[boardHash] = index
+			}
+
+			validateFieldTypes(board, path, index, parsed.locate, collector)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("validating boards cancelled: %w", err)
+	}
+
+	return nil
+}
+
+func validateFieldTypes(board model.Board, path string, index int, locate diagnostics.Locator, collector *diagnostics.Collector) {
+	for _, typed := range model.TypedFields {
+		if value, exists := board.ExtraEntries[typed.Field]; exists {
+			position := locate.Of(fmt.Sprintf("/boards/%d/%v", index, escapePointer(typed.Field)))
+			collector.WarnAt(diagnostics.CodeInvalidType, path, index, position, "Field '%v' of board '%v' must be %v, got %v '%v'", typed.Field, board.Name, typed.Expected, jsonTypeName(value), value)
+		}
+	}
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, int, json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,181 @@
+package core_test
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"boards-merger/internal/utils/logger"
+	"boards-merger/internal/utils/testutils"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateFiles(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{
+		"boards": [
+			{"name": "Board1", "vendor": "VendorA", "has_wifi": "yes"},
+			{"vendor": "VendorA"},
+			{"name": "Board1", "vendor": "VendorA", "core": 5},
+			{"name": "Board2", "vendor": "VendorA", "core": "CoreX", "has_wifi": false}
+		]
+	}`)
+	filePath2 := filepath.Join(dir, "boards-2.json")
+	testutils.WriteToFile(t, filePath2, `{`)
+	// Duplicates across files are merged, they are not reported by validation
+	filePath3 := filepath.Join(dir, "boards-3.csv")
+	testutils.WriteToFile(t, filePath3, "name,vendor\nBoard1,VendorA\n")
+
+	collector := diagnostics.NewCollector()
+	if err := core.ValidateFiles(context.Background(), []string{filePath, filePath2, filePath3}, core.ValidateOptions{Diagnostics: collector}); err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	expected := []diagnostics.Diagnostic{
+		{Severity: diagnostics.Error, Code: diagnostics.CodeInvalidBoard, File: filePath, Index: 1, Position: diagnostics.Position{Line: 4, Column: 4}},
+		{Severity: diagnostics.Warning, Code: diagnostics.CodeInvalidType, File: filePath, Index: 0, Position: diagnostics.Position{Line: 3, Column: 56}},
+		{Severity: diagnostics.Warning, Code: diagnostics.CodeDuplicateInFile, File: filePath, Index: 2, Position: diagnostics.Position{Line: 5, Column: 4}},
+		{Severity: diagnostics.Warning, Code: diagnostics.CodeInvalidType, File: filePath, Index: 2, Position: diagnostics.Position{Line: 5, Column: 52}},
+		{Severity: diagnostics.Error, Code: diagnostics.CodeParseError, File: filePath2, Index: diagnostics.NoIndex, Position: diagnostics.Position{Line: 1, Column: 1}},
+	}
+	findings := collector.Diagnostics(diagnostics.Info)
+	if len(findings) != len(expected) {
+		t.Fatalf("Unexpected findings: got %v, expected %v", findings, expected)
+	}
+	for i := range expected {
+		findings[i].Message = ""
+		if findings[i] != expected[i] {
+			t.Fatalf("Unexpected finding at %v: got %v, expected %v", i, findings[i], expected[i])
+		}
+	}
+}
+
+func TestFindingPositions(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name             string
+		fileName         string
+		data             string
+		expectedCode     string
+		expectedIndex    int
+		expectedPosition diagnostics.Position
+	}{
+		{
+			name:             "JSON syntax error",
+			fileName:         "syntax.json",
+			data:             "{\n  \"boards\": [\n    {\"name\": \"Board1\",, \"vendor\": \"VendorA\"}\n  ]\n}",
+			expectedCode:     diagnostics.CodeParseError,
+			expectedIndex:    diagnostics.NoIndex,
+			expectedPosition: diagnostics.Position{Line: 3, Column: 23},
+		},
+		{
+			name:             "JSON boards of the wrong type",
+			fileName:         "type.json",
+			data:             "{\n  \"boards\": \"Board1\"\n}",
+			expectedCode:     diagnostics.CodeParseError,
+			expectedIndex:    diagnostics.NoIndex,
+			expectedPosition: diagnostics.Position{Line: 2, Column: 13},
+		},
+		{
+			name:             "JSON single board with a mistyped field",
+			fileName:         "single.json",
+			data:             "{\n  \"name\": \"Board1\",\n  \"vendor\": \"VendorA\",\n  \"has_wifi\": \"yes\"\n}",
+			expectedCode:     diagnostics.CodeInvalidType,
+			expectedIndex:    0,
+			expectedPosition: diagnostics.Position{Line: 4, Column: 15},
+		},
+		{
+			name:             "YAML invalid board",
+			fileName:         "invalid.yaml",
+			data:             "boards:\n  - name: Board1\n    vendor: VendorA\n  - name: Board2\n",
+			expectedCode:     diagnostics.CodeInvalidBoard,
+			expectedIndex:    1,
+			expectedPosition: diagnostics.Position{Line: 4, Column: 5},
+		},
+		{
+			name:             "YAML syntax error",
+			fileName:         "syntax.yaml",
+			data:             "boards:\n  - name: Board1\n    vendor: \"VendorA\n",
+			expectedCode:     diagnostics.CodeParseError,
+			expectedIndex:    diagnostics.NoIndex,
+			expectedPosition: diagnostics.Position{Line: 3},
+		},
+		{
+			name:             "CSV malformed row",
+			fileName:         "malformed.csv",
+			data:             "name,vendor\nBoard1,VendorA\nBoard2,Vendor\"B\n",
+			expectedCode:     diagnostics.CodeInvalidBoard,
+			expectedIndex:    1,
+			expectedPosition: diagnostics.Position{Line: 3, Column: 14},
+		},
+		{
+			name:             "CSV mistyped field",
+			fileName:         "type.csv",
+			data:             "name,vendor,has wifi\nBoard1,VendorA,true\nBoard2,VendorA,yes\n",
+			expectedCode:     diagnostics.CodeInvalidType,
+			expectedIndex:    1,
+			expectedPosition: diagnostics.Position{Line: 3, Column: 16},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filePath := filepath.Join(dir, test.fileName)
+			testutils.WriteToFile(t, filePath, test.data)
+
+			collector := diagnostics.NewCollector()
+			if err := core.ValidateFiles(context.Background(), []string{filePath}, core.ValidateOptions{Diagnostics: collector}); err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			findings := collector.Diagnostics(diagnostics.Info)
+			if len(findings) != 1 {
+				t.Fatalf("Unexpected findings: %v", findings)
+			}
+			finding := findings[0]
+			if finding.Code != test.expectedCode || finding.Index != test.expectedIndex || finding.Position != test.expectedPosition {
+				t.Fatalf("Unexpected finding: got %v, expected %v at %v #%v", finding, test.expectedCode, test.expectedPosition, test.expectedIndex)
+			}
+		})
+	}
+}
+
+func TestValidateFilesCoercion(t *testing.T) {
+	logger.Disable()
+
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards.json")
+	testutils.WriteToFile(t, filePath, `{"boards": [{"name": "Board1", "vendor": "VendorA", "has_wifi": "yes"}]}`)
+	unconvertiblePath := filepath.Join(dir, "unconvertible.json")
+	testutils.WriteToFile(t, unconvertiblePath, `{"boards": [{"name": "Board1", "vendor": "VendorA", "has_wifi": "maybe"}]}`)
+
+	// A mistyped value is reported once whatever the coercion, even when it is converted or kept
+	for _, coercion := range model.Coercions {
+		for _, path := range []string{filePath, unconvertiblePath} {
+			t.Run(string(coercion)+" "+filepath.Base(path), func(t *testing.T) {
+				collector := diagnostics.NewCollector()
+				if err := core.ValidateFiles(context.Background(), []string{path}, core.ValidateOptions{Coercion: coercion, Diagnostics: collector}); err != nil {
+					t.Fatalf("Unexpected err: %v", err.Error())
+				}
+
+				findings := collector.Diagnostics(diagnostics.Info)
+				if len(findings) != 1 || findings[0].Code != diagnostics.CodeInvalidType || findings[0].Severity != diagnostics.Warning {
+					t.Fatalf("Unexpected findings: %v", findings)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadVendorRegistry reads a JSON file of canonical vendor names and their aliases, e.g. {"Espressif": ["Espressif Systems"]}
+func LoadVendorRegistry(path string) (*model.VendorRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendor registry: %v", err.Error())
+	}
+
+	var vendors map[string][]string
+	if err := json.Unmarshal(data, &vendors); err != nil {
+		return nil, fmt.Errorf("invalid vendor registry %v: %v", path, err.Error())
+	}
+	return model.NewVendorRegistry(vendors)
+}
+
+// unknownVendor is a vendor without registry entry, located at its first board
+type unknownVendor struct {
+	vendor   string
+	file     string
+	index    int
+	position diagnostics.Position
+	boards   int
+}
+
+// vendorReport gathers the vendors that did not match the registry, in the order they were read
+type vendorReport struct {
+	list  []*unknownVendor
+	index map[string]*unknownVendor
+}
+
+func newVendorReport() *vendorReport {
+	return &vendorReport{index: make(map[string]*unknownVendor)}
+}
+
+// canonicalize replaces the vendor of the board by its canonical name, or records it as unknown
+func (report *vendorReport) canonicalize(registry *model.VendorRegistry, board *model.Board, path string, locate diagnostics.Locator) {
+	canonical, matched := registry.Canonical(board.Vendor)
+	if matched {
+		board.Vendor = canonical
+		return
+	}
+
+	if unknown, exists := report.index[board.Vendor]; exists {
+		unknown.boards++
+		return
+	}
+	index := board.Sources["vendor"].Index
+	unknown := &unknownVendor{vendor: board.Vendor, file: path, index: index, position: locate.Of(fmt.Sprintf("/boards/%d/vendor", index)), boards: 1}
+	report.index[board.Vendor] = unknown
+	report.list = append(report.list, unknown)
+}
+
+func (report *vendorReport) emit(collector *diagnostics.Collector) {
+	for _, unknown := range report.list {
+		collector.WarnAt(diagnostics.CodeUnknownVendor, unknown.file, unknown.index, unknown.position, "Vendor '%v' of %v board(s) does not match any vendor registry entry", unknown.vendor, unknown.boards)
+	}
+}
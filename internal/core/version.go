@@ -0,0 +1,5 @@
+package core
+
+// Version of the tool written to the extended metadata, set at build time with
+// -ldflags "-X boards-merger/internal/core.Version=v1.2.3"
+var Version = "dev"
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/model"
+	"boards-merger/internal/utils/logger"
+	"context"
+	"os"
+	"runtime"
+)
+
+type parsedFile struct {
+	path     string
+	boards   *model.BoardsInfo
+	findings []diagnostics.Diagnostic
+	locate   diagnostics.Locator
+	readErr  error
+	parseErr error
+}
+
+func parseFile(path string, options decodeOptions) parsedFile {
+	parsed := parsedFile{path: path}
+
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		parsed.readErr = err
+		return parsed
+	}
+	logger.Info("Parsing file: %v", path)
+
+	// Findings are buffered per file and replayed by the merger in read order
+	collector := diagnostics.NewCollector()
+	parsed.boards, parsed.locate, parsed.parseErr = decodeBoardsFile(path, fileData, collector, options)
+	parsed.findings = collector.Diagnostics(diagnostics.Info)
+	return parsed
+}
+
+// parseFiles reads and parses the files with a bounded pool of workers, the results are delivered in the order of paths.
+// At most a couple of parsed files per worker are held in memory ahead of the consumer, the channel is closed when all
+// files are delivered or ctx is cancelled.
+func parseFiles(ctx context.Context, paths []string, workers int, options decodeOptions) <-chan parsedFile {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]chan parsedFile, len(paths))
+	for i := range results {
+		results[i] = make(chan parsedFile, 1)
+	}
+
+	jobs := make(chan int)
+	slots := make(chan void, 2*workers)
+	go func() {
+		defer close(jobs)
+		for i := range paths {
+			select {
+			case slots <- void{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				if ctx.Err() != nil {
+					results[i] <- parsedFile{path: paths[i], readErr: ctx.Err()}
+					continue
+				}
+				results[i] <- parseFile(paths[i], options)
+			}
+		}()
+	}
+
+	ordered := make(chan parsedFile)
+	go func() {
+		defer close(ordered)
+		for i := range paths {
+			var parsed parsedFile
+			select {
+			case parsed = <-results[i]:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case ordered <- parsed:
+				<-slots
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ordered
+}
@@ -0,0 +1,292 @@
+package diagnostics
+
+import (
+	"boards-merger/internal/utils/logger"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+// Off silences the findings of a code when used as a severity override
+const Off Severity = -1
+
+func (severity Severity) String() string {
+	switch severity {
+	case Off:
+		return "off"
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+func (severity Severity) MarshalText() ([]byte, error) {
+	return []byte(severity.String()), nil
+}
+
+func (severity *Severity) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "off":
+		*severity = Off
+	case "info":
+		*severity = Info
+	case "warning", "warn":
+		*severity = Warning
+	case "error":
+		*severity = Error
+	default:
+		return fmt.Errorf("unknown severity '%s'", text)
+	}
+	return nil
+}
+
+// Codes identify the kind of a finding
+const (
+	CodeSkippedPath   = "skipped-path"
+	CodeReadError     = "read-error"
+	CodeParseError    = "parse-error"
+	CodeInvalidBoard  = "invalid-board"
+	CodeDuplicate     = "duplicate-board"
+	CodeConflict      = "conflict"
+	CodeSchema        = "schema-violation"
+	CodeFieldAlias    = "field-alias"
+	CodeCoercion      = "type-coercion"
+	CodeInvalidType   = "invalid-type"
+	CodeUnknownVendor = "unknown-vendor"
+	CodeNearDuplicate = "near-duplicate"
+	// Reported by validation only
+	CodeDuplicateInFile = "duplicate-in-file"
+)
+
+var Codes = []string{CodeSkippedPath, CodeReadError, CodeParseError, CodeInvalidBoard, CodeDuplicate, CodeConflict, CodeSchema, CodeFieldAlias, CodeCoercion, CodeInvalidType, CodeUnknownVendor, CodeNearDuplicate, CodeDuplicateInFile}
+
+// ParseSeverityOverrides parses a comma separated list of 'code=severity' pairs, e.g. "duplicate-in-file=error,invalid-type=off"
+func ParseSeverityOverrides(spec string) (map[string]Severity, error) {
+	overrides := make(map[string]Severity)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+
+		code, value, found := strings.Cut(part, "=")
+		code = strings.TrimSpace(code)
+		if !found || !isKnownCode(code) {
+			return nil, fmt.Errorf("invalid severity override '%v', expected 'code=severity' with a code among: %v", part, strings.Join(Codes, ", "))
+		}
+
+		var severity Severity
+		if err := severity.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+			return nil, err
+		}
+		overrides[code] = severity
+	}
+	return overrides, nil
+}
+
+func isKnownCode(code string) bool {
+	for _, known := range Codes {
+		if known == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NoIndex is used for findings that are not tied to a single board of a file
+const NoIndex = -1
+
+// Position is a 1-based line and column within a file, the zero value is an unknown position
+type Position struct {
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// Locator resolves a JSON pointer (e.g. "/boards/3/has_wifi") to its position within a file
+type Locator func(pointer string) Position
+
+// Of returns the zero position for a nil locator
+func (locate Locator) Of(pointer string) Position {
+	if locate == nil {
+		return Position{}
+	}
+	return locate(pointer)
+}
+
+// PositionError is an error located within a file, such as a syntax error
+type PositionError struct {
+	Position Position
+	Err      error
+}
+
+func (err *PositionError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *PositionError) Unwrap() error {
+	return err.Err
+}
+
+// PositionOf returns the position of a PositionError wrapped by err, or the zero position
+func PositionOf(err error) Position {
+	var positionErr *PositionError
+	if errors.As(err, &positionErr) {
+		return positionErr.Position
+	}
+	return Position{}
+}
+
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	File     string   `json:"file,omitempty"`
+	Index    int      `json:"index"`
+	Position
+	Message string `json:"message"`
+}
+
+// Location formats the file, position and board index of the finding, e.g. "boards.json:12:5 #3"
+func (diagnostic Diagnostic) Location() string {
+	location := diagnostic.File
+	if diagnostic.Line > 0 {
+		location = fmt.Sprintf("%v:%v", location, diagnostic.Line)
+	}
+	if diagnostic.Column > 0 {
+		location = fmt.Sprintf("%v:%v", location, diagnostic.Column)
+	}
+	if diagnostic.Index == NoIndex {
+		return location
+	}
+	return fmt.Sprintf("%v #%v", location, diagnostic.Index)
+}
+
+func (diagnostic Diagnostic) describe() string {
+	if location := diagnostic.Location(); len(location) > 0 {
+		return location + ": " + diagnostic.Message
+	}
+	return diagnostic.Message
+}
+
+func (diagnostic Diagnostic) String() string {
+	return fmt.Sprintf("[%v] %v", strings.ToUpper(diagnostic.Severity.String()), diagnostic.describe())
+}
+
+// Collector gathers findings from concurrent readers, a nil collector is valid and only logs findings
+type Collector struct {
+	mu          sync.Mutex
+	diagnostics []Diagnostic
+}
+
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add records the finding and writes it to the logger
+func (collector *Collector) Add(diagnostic Diagnostic) {
+	switch diagnostic.Severity {
+	case Info:
+		logger.Info("%v", diagnostic.describe())
+	case Warning:
+		logger.Warn("%v", diagnostic.describe())
+	default:
+		logger.Error("%v", diagnostic.describe())
+	}
+
+	if collector == nil {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.diagnostics = append(collector.diagnostics, diagnostic)
+}
+
+// Extend records findings that were already logged, e.g. buffered by a worker into its own collector
+func (collector *Collector) Extend(diagnostics []Diagnostic) {
+	if collector == nil {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.diagnostics = append(collector.diagnostics, diagnostics...)
+}
+
+func (collector *Collector) Info(code string, file string, index int, format string, args ...interface{}) {
+	collector.Add(Diagnostic{Severity: Info, Code: code, File: file, Index: index, Message: fmt.Sprintf(format, args...)})
+}
+
+func (collector *Collector) Warn(code string, file string, index int, format string, args ...interface{}) {
+	collector.Add(Diagnostic{Severity: Warning, Code: code, File: file, Index: index, Message: fmt.Sprintf(format, args...)})
+}
+
+func (collector *Collector) Error(code string, file string, index int, format string, args ...interface{}) {
+	collector.Add(Diagnostic{Severity: Error, Code: code, File: file, Index: index, Message: fmt.Sprintf(format, args...)})
+}
+
+func (collector *Collector) InfoAt(code string, file string, index int, position Position, format string, args ...interface{}) {
+	collector.Add(Diagnostic{Severity: Info, Code: code, File: file, Index: index, Position: position, Message: fmt.Sprintf(format, args...)})
+}
+
+func (collector *Collector) WarnAt(code string, file string, index int, position Position, format string, args ...interface{}) {
+	collector.Add(Diagnostic{Severity: Warning, Code: code, File: file, Index: index, Position: position, Message: fmt.Sprintf(format, args...)})
+}
+
+func (collector *Collector) ErrorAt(code string, file string, index int, position Position, format string, args ...interface{}) {
+	collector.Add(Diagnostic{Severity: Error, Code: code, File: file, Index: index, Position: position, Message: fmt.Sprintf(format, args...)})
+}
+
+// Override changes the severity of the findings already recorded for the given codes
+func (collector *Collector) Override(overrides map[string]Severity) {
+	if collector == nil {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	for i, diagnostic := range collector.diagnostics {
+		if severity, exists := overrides[diagnostic.Code]; exists {
+			collector.diagnostics[i].Severity = severity
+		}
+	}
+}
+
+// Diagnostics returns the findings with at least the given severity, in the order they were added
+func (collector *Collector) Diagnostics(minSeverity Severity) []Diagnostic {
+	if collector == nil {
+		return nil
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	var result []Diagnostic
+	for _, diagnostic := range collector.diagnostics {
+		if diagnostic.Severity >= minSeverity {
+			result = append(result, diagnostic)
+		}
+	}
+	return result
+}
+
+func (collector *Collector) Count(severity Severity) int {
+	count := 0
+	for _, diagnostic := range collector.Diagnostics(severity) {
+		if diagnostic.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,91 @@
+package diagnostics_test
+
+import (
+	"boards-merger/internal/diagnostics"
+	"boards-merger/internal/utils/logger"
+	"encoding/json"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	logger.Disable()
+
+	collector := diagnostics.NewCollector()
+	collector.Info(diagnostics.CodeDuplicate, "boards-1.json", 0, "duplicate %v", "Board1")
+	collector.Warn(diagnostics.CodeInvalidBoard, "boards-1.json", 2, "invalid board")
+	collector.Error(diagnostics.CodeParseError, "boards-2.json", diagnostics.NoIndex, "invalid JSON")
+
+	tests := []struct {
+		name          string
+		minSeverity   diagnostics.Severity
+		expectedCodes []string
+	}{
+		{name: "All findings", minSeverity: diagnostics.Info, expectedCodes: []string{diagnostics.CodeDuplicate, diagnostics.CodeInvalidBoard, diagnostics.CodeParseError}},
+		{name: "Warnings and errors", minSeverity: diagnostics.Warning, expectedCodes: []string{diagnostics.CodeInvalidBoard, diagnostics.CodeParseError}},
+		{name: "Errors only", minSeverity: diagnostics.Error, expectedCodes: []string{diagnostics.CodeParseError}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings := collector.Diagnostics(test.minSeverity)
+			if len(findings) != len(test.expectedCodes) {
+				t.Fatalf("Unexpected findings length: got %v, expected %v", len(findings), len(test.expectedCodes))
+			}
+			for i, code := range test.expectedCodes {
+				if findings[i].Code != code {
+					t.Fatalf("Unexpected finding code at %v: got %v, expected %v", i, findings[i].Code, code)
+				}
+			}
+		})
+	}
+
+	if collector.Count(diagnostics.Warning) != 1 || collector.Count(diagnostics.Error) != 1 {
+		t.Fatalf("Unexpected counts: got %v warnings and %v errors", collector.Count(diagnostics.Warning), collector.Count(diagnostics.Error))
+	}
+
+	expectedString := "[WARNING] boards-1.json #2: invalid board"
+	if findings := collector.Diagnostics(diagnostics.Warning); findings[0].String() != expectedString {
+		t.Fatalf("Unexpected string: got %v, expected %v", findings[0].String(), expectedString)
+	}
+
+	data, err := json.Marshal(collector.Diagnostics(diagnostics.Error)[0])
+	if err != nil {
+		t.Fatalf("Unexpected marshalling err: %v", err.Error())
+	}
+	expectedJson := `{"severity":"error","code":"parse-error","file":"boards-2.json","index":-1,"message":"invalid JSON"}`
+	if string(data) != expectedJson {
+		t.Fatalf("Unexpected JSON: got %s, expected %v", data, expectedJson)
+	}
+
+	var nilCollector *diagnostics.Collector
+	nilCollector.Warn(diagnostics.CodeInvalidBoard, "boards-1.json", 0, "ignored")
+	if len(nilCollector.Diagnostics(diagnostics.Info)) != 0 {
+		t.Fatalf("A nil collector should not hold findings")
+	}
+}
+
+func TestSeverityOverrides(t *testing.T) {
+	logger.Disable()
+
+	overrides, err := diagnostics.ParseSeverityOverrides("invalid-type=error, parse-error=off,duplicate-in-file=warn")
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	collector := diagnostics.NewCollector()
+	collector.Warn(diagnostics.CodeInvalidType, "boards-1.json", 0, "invalid type")
+	collector.Error(diagnostics.CodeParseError, "boards-2.json", diagnostics.NoIndex, "invalid JSON")
+	collector.Info(diagnostics.CodeDuplicateInFile, "boards-1.json", 1, "duplicate")
+	collector.Override(overrides)
+
+	findings := collector.Diagnostics(diagnostics.Info)
+	if len(findings) != 2 || findings[0].Severity != diagnostics.Error || findings[1].Severity != diagnostics.Warning {
+		t.Fatalf("Unexpected findings after overrides: %v", findings)
+	}
+
+	for _, spec := range []string{"invalid-type", "unknown-code=error", "invalid-type=fatal"} {
+		if _, err := diagnostics.ParseSeverityOverrides(spec); err == nil {
+			t.Fatalf("Expected an error for '%v'", spec)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package encoders
+
+import (
+	"boards-merger/internal/model"
+	"encoding/csv"
+	"io"
+)
+
+type csvEncoder struct{}
+
+func init() {
+	Register("csv", csvEncoder{})
+}
+
+func (csvEncoder) ContentType() string {
+	return "text/csv"
+}
+
+func (csvEncoder) Encode(w io.Writer, boardsInfo *model.BoardsInfo) error {
+	writer := csv.NewWriter(w)
+	columns := tableColumns(boardsInfo.Boards)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	for _, board := range boardsInfo.Boards {
+		if err := writer.Write(tableRow(board, columns)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
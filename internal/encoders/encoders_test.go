@@ -0,0 +1,167 @@
+package encoders_test
+
+import (
+	"boards-merger/internal/encoders"
+	"boards-merger/internal/model"
+	"boards-merger/internal/utils/testutils"
+	"bytes"
+	"testing"
+)
+
+func TestEncoders(t *testing.T) {
+	boardsInfo := &model.BoardsInfo{
+		Boards: []model.Board{
+			{
+				Name:    "Board1",
+				Vendor:  "VendorA",
+				Core:    "CoreX",
+				HasWiFi: &testutils.BoolTrue,
+				ExtraEntries: map[string]interface{}{
+					"pins":  float64(40),
+					"notes": "a|b",
+				},
+			},
+			{
+				Name:   "Board2",
+				Vendor: "VendorB",
+				ExtraEntries: map[string]interface{}{
+					"enabled": "yes",
+					"ports":   []interface{}{"usb", "uart"},
+				},
+			},
+		},
+		MetaData: model.MetaData{
+			UniqueVendors: 2,
+			TotalBoards:   2,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		format        string
+		groupByVendor bool
+		expectedErr   bool
+		expected      string
+	}{
+		{
+			name:   "CSV with fixed columns first and the union of extra keys",
+			format: "csv",
+			expected: "name,vendor,core,has_wifi,enabled,notes,pins,ports\n" +
+				"Board1,VendorA,CoreX,true,,a|b,40,\n" +
+				"Board2,VendorB,,,yes,,,\"[\"\"usb\"\",\"\"uart\"\"]\"\n",
+		},
+		{
+			name:   "Markdown table with escaped pipes",
+			format: "markdown",
+			expected: "| name | vendor | core | has_wifi | enabled | notes | pins | ports |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| Board1 | VendorA | CoreX | true |  | a\\|b | 40 |  |\n" +
+				"| Board2 | VendorB |  |  | yes |  |  | [\"usb\",\"uart\"] |\n",
+		},
+		{
+			name:   "NDJSON with metadata trailer",
+			format: "ndjson",
+			expected: `{"core":"CoreX","has_wifi":true,"name":"Board1","notes":"a|b","pins":40,"vendor":"VendorA"}` + "\n" +
+				`{"enabled":"yes","name":"Board2","ports":["usb","uart"],"vendor":"VendorB"}` + "\n" +
+				`{"_metadata":{"unique_vendors":2,"total_boards":2}}` + "\n",
+		},
+		{
+			name:   "YAML in block style with ambiguous strings quoted",
+			format: "YAML",
+			expected: "boards:\n" +
+				"  - core: CoreX\n" +
+				"    has_wifi: true\n" +
+				"    name: Board1\n" +
+				"    notes: a|b\n" +
+				"    pins: 40\n" +
+				"    vendor: VendorA\n" +
+				"  - enabled: \"yes\"\n" +
+				"    name: Board2\n" +
+				"    ports:\n" +
+				"      - usb\n" +
+				"      - uart\n" +
+				"    vendor: VendorB\n" +
+				"_metadata:\n" +
+				"  unique_vendors: 2\n" +
+				"  total_boards: 2\n",
+		},
+		{
+			name:          "JSON grouped by vendor",
+			format:        "json",
+			groupByVendor: true,
+			expected: `{
+  "vendors": [
+    {
+      "vendor": "VendorA",
+      "boards": [
+        {
+          "core": "CoreX",
+          "has_wifi": true,
+          "name": "Board1",
+          "notes": "a|b",
+          "pins": 40,
+          "vendor": "VendorA"
+        }
+      ]
+    },
+    {
+      "vendor": "VendorB",
+      "boards": [
+        {
+          "enabled": "yes",
+          "name": "Board2",
+          "ports": [
+            "usb",
+            "uart"
+          ],
+          "vendor": "VendorB"
+        }
+      ]
+    }
+  ],
+  "_metadata": {
+    "unique_vendors": 2,
+    "total_boards": 2
+  }
+}
+`,
+		},
+		{
+			name:          "NDJSON is never grouped",
+			format:        "ndjson",
+			groupByVendor: true,
+			expected: `{"core":"CoreX","has_wifi":true,"name":"Board1","notes":"a|b","pins":40,"vendor":"VendorA"}` + "\n" +
+				`{"enabled":"yes","name":"Board2","ports":["usb","uart"],"vendor":"VendorB"}` + "\n" +
+				`{"_metadata":{"unique_vendors":2,"total_boards":2}}` + "\n",
+		},
+		{
+			name:        "Unknown format",
+			format:      "xml",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder, err := encoders.Get(test.format)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("Unexpected err: %v", err)
+			}
+			if test.expectedErr {
+				return
+			}
+
+			grouped := *boardsInfo
+			grouped.GroupByVendor = test.groupByVendor
+
+			var out bytes.Buffer
+			if err := encoder.Encode(&out, &grouped); err != nil {
+				t.Fatalf("Unexpected encoding err: %v", err.Error())
+			}
+
+			if out.String() != test.expected {
+				t.Errorf("Unexpected output:\ngot:\n%v\nexpected:\n%v", out.String(), test.expected)
+			}
+		})
+	}
+}
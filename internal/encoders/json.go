@@ -0,0 +1,27 @@
+package encoders
+
+import (
+	"boards-merger/internal/model"
+	"encoding/json"
+	"io"
+)
+
+type jsonEncoder struct{}
+
+func init() {
+	Register("json", jsonEncoder{})
+}
+
+func (jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (jsonEncoder) Encode(w io.Writer, boardsInfo *model.BoardsInfo) error {
+	out, err := json.MarshalIndent(boardsInfo, "", "  ")
+	if err != nil {
+		return err
+	}
+	out = append(out, '\n')
+	_, err = w.Write(out)
+	return err
+}
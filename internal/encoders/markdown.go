@@ -0,0 +1,46 @@
+package encoders
+
+import (
+	"boards-merger/internal/model"
+	"bufio"
+	"io"
+	"strings"
+)
+
+type markdownEncoder struct{}
+
+func init() {
+	Register("markdown", markdownEncoder{})
+}
+
+func (markdownEncoder) ContentType() string {
+	return "text/markdown"
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func (markdownEncoder) Encode(w io.Writer, boardsInfo *model.BoardsInfo) error {
+	writer := bufio.NewWriter(w)
+	columns := tableColumns(boardsInfo.Boards)
+
+	writeMarkdownRow(writer, columns)
+	separator := make([]string, len(columns))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(writer, separator)
+
+	for _, board := range boardsInfo.Boards {
+		writeMarkdownRow(writer, tableRow(board, columns))
+	}
+
+	return writer.Flush()
+}
+
+func writeMarkdownRow(writer *bufio.Writer, cells []string) {
+	writer.WriteString("|")
+	for _, cell := range cells {
+		writer.WriteString(" " + markdownEscaper.Replace(cell) + " |")
+	}
+	writer.WriteString("\n")
+}
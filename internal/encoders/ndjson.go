@@ -0,0 +1,34 @@
+package encoders
+
+import (
+	"boards-merger/internal/model"
+	"encoding/json"
+	"io"
+)
+
+type ndjsonEncoder struct{}
+
+func init() {
+	Register("ndjson", ndjsonEncoder{})
+}
+
+func (ndjsonEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+// One board per line, followed by a trailer line holding the metadata, conflicts and near-duplicates
+func (ndjsonEncoder) Encode(w io.Writer, boardsInfo *model.BoardsInfo) error {
+	encoder := json.NewEncoder(w)
+	for _, board := range boardsInfo.Boards {
+		if err := encoder.Encode(board); err != nil {
+			return err
+		}
+	}
+
+	trailer := struct {
+		MetaData       model.MetaData        `json:"_metadata"`
+		Conflicts      []model.Conflict      `json:"_conflicts,omitempty"`
+		NearDuplicates []model.NearDuplicate `json:"_near_duplicates,omitempty"`
+	}{boardsInfo.MetaData, boardsInfo.Conflicts, boardsInfo.NearDuplicates}
+	return encoder.Encode(trailer)
+}
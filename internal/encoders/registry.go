@@ -0,0 +1,39 @@
+package encoders
+
+import (
+	"boards-merger/internal/model"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type Encoder interface {
+	// ContentType is the MIME type used when the encoded output is served over HTTP
+	ContentType() string
+	Encode(w io.Writer, boardsInfo *model.BoardsInfo) error
+}
+
+var registry = make(map[string]Encoder)
+
+// Register makes an encoder available by name, registering the same name twice replaces the previous encoder
+func Register(name string, encoder Encoder) {
+	registry[strings.ToLower(name)] = encoder
+}
+
+func Get(name string) (Encoder, error) {
+	encoder, exists := registry[strings.ToLower(name)]
+	if !exists {
+		return nil, fmt.Errorf("unknown output format '%v', supported formats: %v", name, strings.Join(Names(), ", "))
+	}
+	return encoder, nil
+}
+
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
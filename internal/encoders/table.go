@@ -0,0 +1,76 @@
+package encoders
+
+import (
+	"boards-merger/internal/model"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+var fixedColumns = []string{"name", "vendor", "core", "has_wifi"}
+
+// Fixed board fields come first, followed by the sorted union of all extra entry keys
+func tableColumns(boards []model.Board) []string {
+	extraKeys := make(map[string]struct{})
+	for _, board := range boards {
+		for key := range board.ExtraEntries {
+			extraKeys[key] = struct{}{}
+		}
+	}
+
+	for _, column := range fixedColumns {
+		delete(extraKeys, column)
+	}
+
+	sortedExtraKeys := make([]string, 0, len(extraKeys))
+	for key := range extraKeys {
+		sortedExtraKeys = append(sortedExtraKeys, key)
+	}
+	sort.Strings(sortedExtraKeys)
+
+	return append(append([]string{}, fixedColumns...), sortedExtraKeys...)
+}
+
+func tableRow(board model.Board, columns []string) []string {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case "name":
+			row[i] = board.Name
+		case "vendor":
+			row[i] = board.Vendor
+		case "core":
+			row[i] = board.Core
+		case "has_wifi":
+			if board.HasWiFi != nil {
+				row[i] = strconv.FormatBool(*board.HasWiFi)
+			}
+		default:
+			if value, exists := board.ExtraEntries[column]; exists {
+				row[i] = formatCell(value)
+			}
+		}
+	}
+	return row
+}
+
+// Scalars are printed as is, nested arrays and objects are printed as compact JSON
+func formatCell(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case bool:
+		return strconv.FormatBool(typed)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		out, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprint(typed)
+		}
+		return string(out)
+	}
+}
@@ -0,0 +1,60 @@
+package encoders
+
+import (
+	"boards-merger/internal/model"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type yamlEncoder struct{}
+
+func init() {
+	Register("yaml", yamlEncoder{})
+}
+
+func (yamlEncoder) ContentType() string {
+	return "application/yaml"
+}
+
+// The JSON output is re-read as a YAML node tree, so both formats always share the same keys and key order
+func (yamlEncoder) Encode(w io.Writer, boardsInfo *model.BoardsInfo) error {
+	jsonData, err := json.Marshal(boardsInfo)
+	if err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(jsonData, &document); err != nil {
+		return err
+	}
+	resetStyle(&document)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// Strings that YAML 1.1 parsers would read as booleans are kept quoted for compatibility
+var yaml11Booleans = map[string]struct{}{
+	"y": {}, "yes": {}, "n": {}, "no": {}, "on": {}, "off": {},
+}
+
+// JSON is parsed as flow style YAML, dropping the style switches the encoder back to block style
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		if _, exists := yaml11Booleans[strings.ToLower(node.Value)]; exists {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	}
+
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
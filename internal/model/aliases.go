@@ -0,0 +1,121 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BoardFields are the board keys decoded into the dedicated Board fields
+var BoardFields = []string{"name", "vendor", "core", "has_wifi"}
+
+// DefaultAliases are the non-standard keys commonly used by vendors for each board field
+var DefaultAliases = map[string][]string{
+	"name":     {"board", "board_name", "boardName"},
+	"vendor":   {"manufacturer", "maker", "brand"},
+	"core":     {"mcu", "cpu", "processor"},
+	"has_wifi": {"wifi", "hasWifi"},
+}
+
+func isField(key string) bool {
+	for _, field := range BoardFields {
+		if field == key {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldAliases maps incoming keys onto the board fields, a nil table maps nothing
+type FieldAliases struct {
+	fields     map[string]string
+	ignoreCase bool
+}
+
+// AppliedAlias is an incoming key that was renamed to a board field
+type AppliedAlias struct {
+	Alias string `json:"alias"`
+	Field string `json:"field"`
+}
+
+// NewFieldAliases builds a table from aliases grouped by board field. When ignoreCase is set, aliases and the
+// board fields themselves match keys regardless of case, e.g. "Manufacturer" or "Name".
+func NewFieldAliases(aliases map[string][]string, ignoreCase bool) (*FieldAliases, error) {
+	table := &FieldAliases{fields: make(map[string]string), ignoreCase: ignoreCase}
+
+	fields := make([]string, 0, len(aliases))
+	for field := range aliases {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if !isField(field) {
+			return nil, fmt.Errorf("cannot alias unknown field '%v', expected one of: %v", field, strings.Join(BoardFields, ", "))
+		}
+
+		for _, alias := range aliases[field] {
+			key := table.normalize(alias)
+			if len(key) == 0 {
+				return nil, fmt.Errorf("empty alias for field '%v'", field)
+			}
+			if isField(key) {
+				return nil, fmt.Errorf("alias '%v' of field '%v' is itself a board field", alias, field)
+			}
+			if existing, exists := table.fields[key]; exists && existing != field {
+				return nil, fmt.Errorf("alias '%v' is used for both '%v' and '%v'", alias, existing, field)
+			}
+			table.fields[key] = field
+		}
+	}
+
+	return table, nil
+}
+
+func (aliases *FieldAliases) normalize(key string) string {
+	key = strings.TrimSpace(key)
+	if aliases.ignoreCase {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// Field returns the board field an incoming key stands for, when the key is an alias or a differently cased field
+func (aliases *FieldAliases) Field(key string) (string, bool) {
+	if aliases == nil {
+		return "", false
+	}
+
+	key = aliases.normalize(key)
+	if field, exists := aliases.fields[key]; exists {
+		return field, true
+	}
+	if aliases.ignoreCase && isField(key) {
+		return key, true
+	}
+	return "", false
+}
+
+// Apply renames the aliased keys of a board object in place. Keys are visited in order, and an alias is left untouched
+// when its field is already set, so the exact field key always takes precedence.
+func (aliases *FieldAliases) Apply(object map[string]interface{}) []AppliedAlias {
+	if aliases == nil {
+		return nil
+	}
+
+	var applied []AppliedAlias
+	for _, key := range sortedEntryKeys(object) {
+		field, exists := aliases.Field(key)
+		if !exists || field == key {
+			continue
+		}
+		if _, taken := object[field]; taken {
+			continue
+		}
+
+		object[field] = object[key]
+		delete(object, key)
+		applied = append(applied, AppliedAlias{Alias: key, Field: field})
+	}
+	return applied
+}
@@ -0,0 +1,96 @@
+package model_test
+
+import (
+	"boards-merger/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestFieldAliasesApply(t *testing.T) {
+	tests := []struct {
+		name            string
+		ignoreCase      bool
+		object          map[string]interface{}
+		expectedObject  map[string]interface{}
+		expectedApplied []model.AppliedAlias
+	}{
+		{
+			name:           "Aliases renamed to their fields",
+			object:         map[string]interface{}{"board_name": "Board1", "manufacturer": "VendorA", "wifi": true, "ram": "4MB"},
+			expectedObject: map[string]interface{}{"name": "Board1", "vendor": "VendorA", "has_wifi": true, "ram": "4MB"},
+			expectedApplied: []model.AppliedAlias{
+				{Alias: "board_name", Field: "name"},
+				{Alias: "manufacturer", Field: "vendor"},
+				{Alias: "wifi", Field: "has_wifi"},
+			},
+		},
+		{
+			name:            "Field key takes precedence over its aliases",
+			object:          map[string]interface{}{"vendor": "VendorA", "brand": "VendorB", "maker": "VendorC"},
+			expectedObject:  map[string]interface{}{"vendor": "VendorA", "brand": "VendorB", "maker": "VendorC"},
+			expectedApplied: nil,
+		},
+		{
+			name:            "First alias in key order wins",
+			object:          map[string]interface{}{"maker": "VendorC", "brand": "VendorB"},
+			expectedObject:  map[string]interface{}{"vendor": "VendorB", "maker": "VendorC"},
+			expectedApplied: []model.AppliedAlias{{Alias: "brand", Field: "vendor"}},
+		},
+		{
+			name:            "Case sensitive by default",
+			object:          map[string]interface{}{"Name": "Board1", "Manufacturer": "VendorA"},
+			expectedObject:  map[string]interface{}{"Name": "Board1", "Manufacturer": "VendorA"},
+			expectedApplied: nil,
+		},
+		{
+			name:           "Case insensitive aliases and fields",
+			ignoreCase:     true,
+			object:         map[string]interface{}{"Name": "Board1", "MANUFACTURER": "VendorA", "HasWiFi": false},
+			expectedObject: map[string]interface{}{"name": "Board1", "vendor": "VendorA", "has_wifi": false},
+			expectedApplied: []model.AppliedAlias{
+				{Alias: "HasWiFi", Field: "has_wifi"},
+				{Alias: "MANUFACTURER", Field: "vendor"},
+				{Alias: "Name", Field: "name"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aliases, err := model.NewFieldAliases(model.DefaultAliases, test.ignoreCase)
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			applied := aliases.Apply(test.object)
+			if !reflect.DeepEqual(test.object, test.expectedObject) {
+				t.Fatalf("Unexpected object: got %v, expected %v", test.object, test.expectedObject)
+			}
+			if !reflect.DeepEqual(applied, test.expectedApplied) {
+				t.Fatalf("Unexpected applied aliases: got %v, expected %v", applied, test.expectedApplied)
+			}
+		})
+	}
+}
+
+func TestNewFieldAliasesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		aliases    map[string][]string
+		ignoreCase bool
+	}{
+		{name: "Unknown field", aliases: map[string][]string{"ram": {"memory"}}},
+		{name: "Empty alias", aliases: map[string][]string{"vendor": {" "}}},
+		{name: "Alias is a field", aliases: map[string][]string{"vendor": {"name"}}},
+		{name: "Alias is a field ignoring case", aliases: map[string][]string{"vendor": {"Name"}}, ignoreCase: true},
+		{name: "Alias of two fields", aliases: map[string][]string{"vendor": {"make"}, "name": {"make"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := model.NewFieldAliases(test.aliases, test.ignoreCase); err == nil {
+				t.Fatalf("Expected an error")
+			}
+		})
+	}
+}
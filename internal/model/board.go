@@ -1,9 +1,9 @@
 package model
 
 import (
-	"boards-merger/internal/utils/logger"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -13,6 +13,11 @@ type Board struct {
 	Core         string
 	HasWiFi      *bool
 	ExtraEntries map[string]interface{}
+	// Sources maps the name, vendor, core & has_wifi fields to the entry they were taken from
+	Sources map[string]Source
+	// ExtraSources maps the extra entries to the entry they were taken from, apart from Sources since a mistyped
+	// extra entry shares its key with a typed field, e.g. "has_wifi": "yes" next to a has_wifi read from another file
+	ExtraSources map[string]Source
 }
 
 func sanitizeMapKeys(data map[string]interface{}) map[string]interface{} {
@@ -31,6 +36,11 @@ func (board *Board) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	return board.FromMap(rawMap)
+}
+
+// FromMap fills the board from a decoded key-value object, applying the same validation rules as UnmarshalJSON
+func (board *Board) FromMap(rawMap map[string]interface{}) error {
 	// Trim leading and trailing spaces from keys
 	sanRawMap := sanitizeMapKeys(rawMap)
 
@@ -38,7 +48,7 @@ func (board *Board) UnmarshalJSON(data []byte) error {
 	name, exists := sanRawMap["name"].(string)
 	name = strings.TrimSpace(name)
 	if !exists || len(name) == 0 {
-		return fmt.Errorf("JSON object missing critical data: Board name")
+		return fmt.Errorf("object missing critical data: Board name")
 	}
 	board.Name = name
 	delete(sanRawMap, "name")
@@ -47,7 +57,7 @@ func (board *Board) UnmarshalJSON(data []byte) error {
 	vendor, exists := sanRawMap["vendor"].(string)
 	vendor = strings.TrimSpace(vendor)
 	if !exists || len(vendor) == 0 {
-		return fmt.Errorf("JSON object missing critical data: Board vendor")
+		return fmt.Errorf("object missing critical data: Board vendor")
 	}
 	board.Vendor = vendor
 	delete(sanRawMap, "vendor")
@@ -67,6 +77,10 @@ func (board *Board) UnmarshalJSON(data []byte) error {
 		delete(sanRawMap, "has_wifi")
 	}
 
+	// '_sources' is reserved for the provenance of merged outputs, it describes the file they were written from,
+	// so reading an output back never turns stale sources into an extra property
+	delete(sanRawMap, "_sources")
+
 	// Preserve all extra properties
 	board.ExtraEntries = sanRawMap
 
@@ -74,11 +88,15 @@ func (board *Board) UnmarshalJSON(data []byte) error {
 }
 
 func (board Board) MarshalJSON() ([]byte, error) {
-	result := map[string]interface{}{
-		"name":   board.Name,
-		"vendor": board.Vendor,
+	result := make(map[string]interface{}, len(board.ExtraEntries)+4)
+	for key, value := range board.ExtraEntries {
+		result[key] = value
 	}
 
+	// Typed fields take precedence over mistyped values of the same key kept as extra entries
+	result["name"] = board.Name
+	result["vendor"] = board.Vendor
+
 	if board.Core != "" {
 		result["core"] = board.Core
 	}
@@ -87,41 +105,168 @@ func (board Board) MarshalJSON() ([]byte, error) {
 		result["has_wifi"] = *board.HasWiFi
 	}
 
-	for key, value := range board.ExtraEntries {
-		result[key] = value
+	if len(board.Sources) > 0 || len(board.ExtraSources) > 0 {
+		sources := make(map[string]Source, len(board.Sources)+len(board.ExtraSources))
+		for key, source := range board.ExtraSources {
+			sources[key] = source
+		}
+		// As for their values, typed fields take precedence over extra entries of the same key
+		for field, source := range board.Sources {
+			sources[field] = source
+		}
+		result["_sources"] = sources
 	}
 
 	return json.Marshal(result)
 }
 
+// Merge combines the other entry of the same board, read after this one, using the default (last-wins) strategy
 func (board *Board) Merge(other Board) error {
+	_, err := board.MergeWith(other, MergeStrategy{})
+	return err
+}
+
+// MergeWith combines the other entry of the same board, read after this one.
+// Missing fields never conflict, conflicting values are resolved per field by the strategy and returned as conflicts.
+func (board *Board) MergeWith(other Board, strategy MergeStrategy) ([]Conflict, error) {
 	if board.Name != other.Name || board.Vendor != other.Vendor {
-		return fmt.Errorf("cannot merge boards with different name or vendor")
+		return nil, fmt.Errorf("cannot merge boards with different name or vendor")
+	}
+
+	// Sources & extra entries are copied so merging never mutates maps shared with other boards
+	merger := boardMerger{
+		board:        board,
+		other:        &other,
+		strategy:     strategy,
+		sources:      make(map[string]Source, len(board.Sources)+len(other.Sources)),
+		extraSources: make(map[string]Source, len(board.ExtraSources)+len(other.ExtraSources)),
+	}
+	for field, source := range board.Sources {
+		merger.sources[field] = source
+	}
+	for key, source := range board.ExtraSources {
+		merger.extraSources[key] = source
 	}
 
 	if other.Core != "" {
 		if board.Core == "" {
 			board.Core = other.Core
-		} else if board.Core != other.Core {
-			logger.Warn("Two entries for %v boards have conflicting info about 'Core': {'%v', '%v'}, choosing '%v'", board.Name, board.Core, other.Core, board.Core)
+			merger.takeSource("core", false)
+		} else if isConflict(board.Core, other.Core) {
+			chosen, err := merger.resolve("core", false, board.Core, other.Core)
+			if err != nil {
+				return nil, err
+			}
+			board.Core = chosen.(string)
 		}
 	}
 
 	if other.HasWiFi != nil {
 		if board.HasWiFi == nil {
 			board.HasWiFi = other.HasWiFi
-		} else if *board.HasWiFi != *other.HasWiFi {
-			logger.Warn("Two entries for %v boards have conflicting info about 'Has Wifi', choosing '%v'", board.Name, *board.HasWiFi)
+			merger.takeSource("has_wifi", false)
+		} else if isConflict(*board.HasWiFi, *other.HasWiFi) {
+			chosen, err := merger.resolve("has_wifi", false, *board.HasWiFi, *other.HasWiFi)
+			if err != nil {
+				return nil, err
+			}
+			hasWiFi := chosen.(bool)
+			board.HasWiFi = &hasWiFi
 		}
 	}
 
-	if board.ExtraEntries == nil && other.ExtraEntries != nil {
-		board.ExtraEntries = make(map[string]interface{})
+	mergedEntries := make(map[string]interface{}, len(board.ExtraEntries)+len(other.ExtraEntries))
+	for key, value := range board.ExtraEntries {
+		mergedEntries[key] = value
 	}
-	for key, value := range other.ExtraEntries {
-		// Conflicting values for the same key will be overridden
-		board.ExtraEntries[key] = value
+	// Keys are visited in order so conflicts are always reported in the same order
+	for _, key := range sortedEntryKeys(other.ExtraEntries) {
+		value := other.ExtraEntries[key]
+		current, exists := mergedEntries[key]
+		if !exists {
+			mergedEntries[key] = value
+			merger.takeSource(key, true)
+			continue
+		} else if !isConflict(current, value) {
+			continue
+		}
+
+		chosen, err := merger.resolve(key, true, current, value)
+		if err != nil {
+			return nil, err
+		}
+		mergedEntries[key] = chosen
+	}
+	if len(mergedEntries) > 0 {
+		board.ExtraEntries = mergedEntries
 	}
 
-	return nil
+	if len(merger.sources) > 0 {
+		board.Sources = merger.sources
+	}
+	if len(merger.extraSources) > 0 {
+		board.ExtraSources = merger.extraSources
+	}
+
+	return merger.conflicts, nil
+}
+
+func sortedEntryKeys(entries map[string]interface{}) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type boardMerger struct {
+	board        *Board
+	other        *Board
+	strategy     MergeStrategy
+	sources      map[string]Source
+	extraSources map[string]Source
+	conflicts    []Conflict
+}
+
+// sourceMaps returns the merged and the incoming sources of the typed fields, or of the extra entries
+func (merger *boardMerger) sourceMaps(extra bool) (map[string]Source, map[string]Source) {
+	if extra {
+		return merger.extraSources, merger.other.ExtraSources
+	}
+	return merger.sources, merger.other.Sources
+}
+
+func (merger *boardMerger) takeSource(field string, extra bool) {
+	sources, incoming := merger.sourceMaps(extra)
+	if source, exists := incoming[field]; exists {
+		sources[field] = source
+	}
+}
+
+func (merger *boardMerger) resolve(field string, extra bool, current interface{}, incoming interface{}) (interface{}, error) {
+	board := merger.board
+	chosen, err := merger.strategy.resolve(field, current, incoming)
+	if err != nil {
+		return nil, fmt.Errorf("board '%v' made by '%v': %w", board.Name, board.Vendor, err)
+	}
+
+	sources, incomingSources := merger.sourceMaps(extra)
+	merger.conflicts = append(merger.conflicts, Conflict{
+		Name:   board.Name,
+		Vendor: board.Vendor,
+		Field:  field,
+		Candidates: []Candidate{
+			{Value: current, Source: sources[field]},
+			{Value: incoming, Source: incomingSources[field]},
+		},
+		Chosen: chosen,
+	})
+
+	// When the incoming value is chosen, its source replaces the current one
+	if !isConflict(chosen, incoming) {
+		merger.takeSource(field, extra)
+	}
+
+	return chosen, nil
 }
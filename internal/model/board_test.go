@@ -40,6 +40,23 @@ func TestBoardUnmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Sources of a merged output are not an extra property",
+			setup: `{
+                "name":   "Board1",
+                "vendor": "VendorA",
+                "_sources": {"name": {"file": "boards-1.json", "index": 0}},
+                "pins": 40
+			}`,
+			expectedErr: false,
+			expectedBoard: model.Board{
+				Name:   "Board1",
+				Vendor: "VendorA",
+				ExtraEntries: map[string]interface{}{
+					"pins": float64(40),
+				},
+			},
+		},
 		{
 			name:          "Empty JSON",
 			setup:         `{ }`,
@@ -147,6 +164,27 @@ func TestBoardMarshal(t *testing.T) {
 	}
 }
 
+func TestBoardMarshalTypedFieldsPrecedence(t *testing.T) {
+	board := model.Board{
+		Name:    "Board1",
+		Vendor:  "VendorA",
+		HasWiFi: &testutils.BoolTrue,
+		ExtraEntries: map[string]interface{}{
+			"has_wifi": "no",
+		},
+	}
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		t.Fatalf("Unexpected marshalling err: %v", err.Error())
+	}
+
+	expected := `{"has_wifi":true,"name":"Board1","vendor":"VendorA"}`
+	if string(data) != expected {
+		t.Fatalf("Unexpected JSON: got %v, expected %v", string(data), expected)
+	}
+}
+
 func TestBoardMerge(t *testing.T) {
 	board1 := model.Board{
 		Name:    "Board1",
@@ -194,3 +232,183 @@ func TestBoardMerge(t *testing.T) {
 
 	testutils.CompareBoards(t, expectedBoard, board1)
 }
+
+func TestBoardMergeStrategies(t *testing.T) {
+	first := model.Board{
+		Name:    "Board1",
+		Vendor:  "VendorA",
+		Core:    "CoreX",
+		HasWiFi: &testutils.BoolTrue,
+		ExtraEntries: map[string]interface{}{
+			"revision": "A",
+			"notes":    "",
+		},
+	}
+	last := model.Board{
+		Name:    "Board1",
+		Vendor:  "VendorA",
+		Core:    "CoreY",
+		HasWiFi: &testutils.BoolFalse,
+		ExtraEntries: map[string]interface{}{
+			"revision": "",
+			"notes":    "second",
+		},
+	}
+
+	tests := []struct {
+		name              string
+		strategy          string
+		expectedErr       bool
+		expectedBoard     model.Board
+		expectedConflicts []string
+	}{
+		{
+			name:              "Default strategy keeps the latest values",
+			strategy:          "",
+			expectedConflicts: []string{"core", "has_wifi", "notes", "revision"},
+			expectedBoard: model.Board{
+				Name: "Board1", Vendor: "VendorA", Core: "CoreY", HasWiFi: &testutils.BoolFalse,
+				ExtraEntries: map[string]interface{}{"revision": "", "notes": "second"},
+			},
+		},
+		{
+			name:     "First wins",
+			strategy: "first-wins",
+			expectedBoard: model.Board{
+				Name: "Board1", Vendor: "VendorA", Core: "CoreX", HasWiFi: &testutils.BoolTrue,
+				ExtraEntries: map[string]interface{}{"revision": "A", "notes": ""},
+			},
+		},
+		{
+			name:     "Prefer non empty",
+			strategy: "prefer-non-empty",
+			expectedBoard: model.Board{
+				Name: "Board1", Vendor: "VendorA", Core: "CoreY", HasWiFi: &testutils.BoolFalse,
+				ExtraEntries: map[string]interface{}{"revision": "A", "notes": "second"},
+			},
+		},
+		{
+			name:     "Per-field overrides",
+			strategy: "last-wins,core=first-wins,revision=first-wins",
+			expectedBoard: model.Board{
+				Name: "Board1", Vendor: "VendorA", Core: "CoreX", HasWiFi: &testutils.BoolFalse,
+				ExtraEntries: map[string]interface{}{"revision": "A", "notes": "second"},
+			},
+		},
+		{
+			name:        "Fail on conflict",
+			strategy:    "fail-on-conflict",
+			expectedErr: true,
+		},
+		{
+			name:        "Fail on conflict for an extra entry only",
+			strategy:    "first-wins,notes=fail-on-conflict",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			strategy, err := model.ParseMergeStrategy(test.strategy)
+			if err != nil {
+				t.Fatalf("Unexpected strategy parsing err: %v", err.Error())
+			}
+
+			board := first
+			conflicts, err := board.MergeWith(last, strategy)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("Unexpected err: %v", err)
+			}
+
+			if test.expectedConflicts != nil {
+				fields := make([]string, 0, len(conflicts))
+				for _, conflict := range conflicts {
+					fields = append(fields, conflict.Field)
+				}
+				if !reflect.DeepEqual(fields, test.expectedConflicts) {
+					t.Fatalf("Unexpected conflicts: got %v, expected %v", fields, test.expectedConflicts)
+				}
+			}
+
+			if !test.expectedErr {
+				testutils.CompareBoards(t, test.expectedBoard, board)
+				if *board.HasWiFi != *test.expectedBoard.HasWiFi {
+					t.Fatalf("Unexpected board has_wifi: got %v, expected %v", *board.HasWiFi, *test.expectedBoard.HasWiFi)
+				}
+			}
+
+			if first.ExtraEntries["revision"] != "A" || first.ExtraEntries["notes"] != "" {
+				t.Fatalf("Merge mutated the extra entries of the original board: %v", first.ExtraEntries)
+			}
+		})
+	}
+}
+
+func TestParseMergeStrategy(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expectedErr bool
+		expected    string
+	}{
+		{name: "Empty", spec: "", expected: "last-wins"},
+		{name: "Default only", spec: "first-wins", expected: "first-wins"},
+		{name: "Overrides without default", spec: " core = fail-on-conflict ", expected: "last-wins,core=fail-on-conflict"},
+		{name: "Default and overrides", spec: "prefer-non-empty,has_wifi=first-wins,core=last-wins", expected: "prefer-non-empty,core=last-wins,has_wifi=first-wins"},
+		{name: "Unknown resolution", spec: "newest-wins", expectedErr: true},
+		{name: "Unknown override resolution", spec: "core=newest-wins", expectedErr: true},
+		{name: "Multiple defaults", spec: "first-wins,last-wins", expectedErr: true},
+		{name: "Override of an identity field", spec: "name=first-wins", expectedErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			strategy, err := model.ParseMergeStrategy(test.spec)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("Unexpected err: %v", err)
+			}
+
+			if !test.expectedErr && strategy.String() != test.expected {
+				t.Fatalf("Unexpected strategy: got %v, expected %v", strategy.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestBoardMergeConflictSources(t *testing.T) {
+	first := model.Board{Name: "Board1", Vendor: "VendorA", Core: "CoreX"}
+	first.SetSource(model.Source{File: "boards-1.json", Index: 2})
+	last := model.Board{Name: "Board1", Vendor: "VendorA", Core: "CoreY", HasWiFi: &testutils.BoolTrue}
+	last.SetSource(model.Source{File: "boards-2.json", Index: 0})
+
+	conflicts, err := first.MergeWith(last, model.MergeStrategy{Default: model.FirstWins})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	expectedConflicts := []model.Conflict{
+		{
+			Name:   "Board1",
+			Vendor: "VendorA",
+			Field:  "core",
+			Candidates: []model.Candidate{
+				{Value: "CoreX", Source: model.Source{File: "boards-1.json", Index: 2}},
+				{Value: "CoreY", Source: model.Source{File: "boards-2.json", Index: 0}},
+			},
+			Chosen: "CoreX",
+		},
+	}
+	if !reflect.DeepEqual(conflicts, expectedConflicts) {
+		t.Fatalf("Unexpected conflicts: got %v, expected %v", conflicts, expectedConflicts)
+	}
+
+	expectedSources := map[string]model.Source{
+		"name":     {File: "boards-1.json", Index: 2},
+		"vendor":   {File: "boards-1.json", Index: 2},
+		"core":     {File: "boards-1.json", Index: 2},
+		"has_wifi": {File: "boards-2.json", Index: 0},
+	}
+	if !reflect.DeepEqual(first.Sources, expectedSources) {
+		t.Fatalf("Unexpected sources: got %v, expected %v", first.Sources, expectedSources)
+	}
+}
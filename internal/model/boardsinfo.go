@@ -1,24 +1,45 @@
 package model
 
 import (
-	"boards-merger/internal/utils/logger"
+	"boards-merger/internal/diagnostics"
 	"encoding/json"
 	"fmt"
 )
 
 type BoardsInfo struct {
-	Boards   []Board  `json:"boards"`
-	MetaData MetaData `json:"_metadata"`
+	Boards         []Board         `json:"boards"`
+	MetaData       MetaData        `json:"_metadata"`
+	Conflicts      []Conflict      `json:"_conflicts,omitempty"`
+	NearDuplicates []NearDuplicate `json:"_near_duplicates,omitempty"`
+	// GroupByVendor nests the boards under their vendor in the JSON output, as a 'vendors' list of VendorGroup
+	GroupByVendor bool `json:"-"`
+}
+
+// VendorGroup holds the boards of a vendor in the grouped JSON output
+type VendorGroup struct {
+	Vendor string  `json:"vendor"`
+	Boards []Board `json:"boards"`
 }
 
 type MetaData struct {
 	UniqueVendors int `json:"unique_vendors"`
 	TotalBoards   int `json:"total_boards"`
+	// ExtendedMetaData is nil unless requested, its fields are written next to the counts
+	*ExtendedMetaData
 }
 
 func (boardinfo *BoardsInfo) UnmarshalJSON(data []byte) error {
+	return boardinfo.Decode(data, "", nil, nil)
+}
+
+// Decode parses a JSON boards list or a single board object read from file, skipped boards are reported to the collector
+// at the position given by locate, which may be nil
+func (boardinfo *BoardsInfo) Decode(data []byte, file string, collector *diagnostics.Collector, locate diagnostics.Locator) error {
 	var tempBoards struct {
-		Boards []json.RawMessage `json:"boards"`
+		Boards  []json.RawMessage `json:"boards"`
+		Vendors []struct {
+			Boards []json.RawMessage `json:"boards"`
+		} `json:"vendors"`
 	}
 
 	err := json.Unmarshal(data, &tempBoards)
@@ -26,25 +47,117 @@ func (boardinfo *BoardsInfo) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	for _, rawBoard := range tempBoards.Boards {
+	rawBoards, pointers := tempBoards.Boards, make([]string, len(tempBoards.Boards))
+	for i := range rawBoards {
+		pointers[i] = fmt.Sprintf("/boards/%d", i)
+	}
+	// Boards of a grouped output are read in order, indexed as a single list
+	if len(rawBoards) == 0 {
+		for g, group := range tempBoards.Vendors {
+			for i, rawBoard := range group.Boards {
+				rawBoards = append(rawBoards, rawBoard)
+				pointers = append(pointers, fmt.Sprintf("/vendors/%d/boards/%d", g, i))
+			}
+		}
+	}
+
+	for i, rawBoard := range rawBoards {
 		var board Board
 		if err := json.Unmarshal(rawBoard, &board); err != nil {
-			logger.Warn("Skipping board due to board parsing error: %v", err.Error())
+			collector.WarnAt(diagnostics.CodeInvalidBoard, file, i, locate.Of(pointers[i]), "Skipping board due to board parsing error: %v", err.Error())
 		} else {
+			board.SetSource(Source{File: file, Index: i})
 			boardinfo.Boards = append(boardinfo.Boards, board)
 		}
 	}
 
 	// Try to unmarshal a single JSON board object
-	if len(tempBoards.Boards) == 0 {
-		logger.Info("Attempting to parse a single board")
+	if len(rawBoards) == 0 {
 		var singleboard Board
 		if err := json.Unmarshal(data, &singleboard); err != nil {
-			logger.Error("Failed parsing a single board object: %v", err.Error())
-			return fmt.Errorf("failed to parse JSON boards list or a single board object")
+			return fmt.Errorf("failed to parse JSON boards list or a single board object: %v", err.Error())
 		}
+		singleboard.SetSource(Source{File: file, Index: 0})
 		boardinfo.Boards = append(boardinfo.Boards, singleboard)
 	}
 
 	return nil
 }
+
+func (boardinfo BoardsInfo) MarshalJSON() ([]byte, error) {
+	type plainBoardsInfo BoardsInfo
+	if !boardinfo.GroupByVendor {
+		return json.Marshal(plainBoardsInfo(boardinfo))
+	}
+
+	return json.Marshal(struct {
+		Vendors        []VendorGroup   `json:"vendors"`
+		MetaData       MetaData        `json:"_metadata"`
+		Conflicts      []Conflict      `json:"_conflicts,omitempty"`
+		NearDuplicates []NearDuplicate `json:"_near_duplicates,omitempty"`
+	}{boardinfo.VendorGroups(), boardinfo.MetaData, boardinfo.Conflicts, boardinfo.NearDuplicates})
+}
+
+// VendorGroups groups the boards by vendor, vendors are listed in the order of their first board
+func (boardinfo *BoardsInfo) VendorGroups() []VendorGroup {
+	groups := make([]VendorGroup, 0)
+	indexes := make(map[string]int)
+	for _, board := range boardinfo.Boards {
+		i, exists := indexes[board.Vendor]
+		if !exists {
+			i = len(groups)
+			indexes[board.Vendor] = i
+			groups = append(groups, VendorGroup{Vendor: board.Vendor})
+		}
+		groups[i].Boards = append(groups[i].Boards, board)
+	}
+	return groups
+}
+
+func (boardinfo *BoardsInfo) UpdateMetaData() {
+	vendorSet := make(map[string]struct{})
+	for _, board := range boardinfo.Boards {
+		vendorSet[board.Vendor] = struct{}{}
+	}
+
+	metadata := MetaData{
+		UniqueVendors: len(vendorSet),
+		TotalBoards:   len(boardinfo.Boards),
+	}
+	if boardinfo.MetaData.ExtendedMetaData != nil {
+		metadata.ExtendedMetaData = boardinfo.MetaData.ExtendedMetaData.recompute(boardinfo.Boards)
+	}
+	boardinfo.MetaData = metadata
+}
+
+// Filter returns a new BoardsInfo holding the boards accepted by keep, their conflicts and near-duplicates, with its metadata recomputed.
+// The file counts and merged duplicates of the extended metadata still describe the whole catalog.
+func (boardinfo *BoardsInfo) Filter(keep func(board Board) bool) *BoardsInfo {
+	filtered := &BoardsInfo{Boards: make([]Board, 0, len(boardinfo.Boards)), GroupByVendor: boardinfo.GroupByVendor}
+	filtered.MetaData.ExtendedMetaData = boardinfo.MetaData.ExtendedMetaData
+	keptBoards := make(map[[2]string]struct{})
+	for _, board := range boardinfo.Boards {
+		if keep(board) {
+			filtered.Boards = append(filtered.Boards, board)
+			keptBoards[[2]string{board.Vendor, board.Name}] = struct{}{}
+		}
+	}
+
+	for _, conflict := range boardinfo.Conflicts {
+		if _, exists := keptBoards[[2]string{conflict.Vendor, conflict.Name}]; exists {
+			filtered.Conflicts = append(filtered.Conflicts, conflict)
+		}
+	}
+
+	// A pair is kept with either of its boards, since the second board no longer exists when the pair was merged
+	for _, pair := range boardinfo.NearDuplicates {
+		_, keptFirst := keptBoards[[2]string{pair.Vendor, pair.Names[0]}]
+		_, keptSecond := keptBoards[[2]string{pair.Vendor, pair.Names[1]}]
+		if keptFirst || keptSecond {
+			filtered.NearDuplicates = append(filtered.NearDuplicates, pair)
+		}
+	}
+
+	filtered.UpdateMetaData()
+	return filtered
+}
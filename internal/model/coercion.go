@@ -0,0 +1,144 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Coercion decides what happens to a typed board field holding a value of another type, e.g. "has_wifi": "yes"
+type Coercion string
+
+const (
+	// NoCoercion keeps mistyped values as extra entries, it is also the behavior of the zero value
+	NoCoercion Coercion = "off"
+	// StrictCoercion rejects mistyped values, the board is kept without them
+	StrictCoercion Coercion = "strict"
+	// LenientCoercion converts known truthy & falsy strings and numbers, other mistyped values are kept as extra entries
+	LenientCoercion Coercion = "lenient"
+)
+
+var Coercions = []Coercion{NoCoercion, StrictCoercion, LenientCoercion}
+
+func ParseCoercion(value string) (Coercion, error) {
+	for _, coercion := range Coercions {
+		if string(coercion) == value {
+			return coercion, nil
+		}
+	}
+	return "", fmt.Errorf("unknown coercion '%v', expected one of: off, strict, lenient", value)
+}
+
+// Enabled reports whether the coercion changes mistyped values at all
+func (coercion Coercion) Enabled() bool {
+	return coercion == StrictCoercion || coercion == LenientCoercion
+}
+
+// CoercedValue is a mistyped value of a typed field, either converted, rejected or kept
+type CoercedValue struct {
+	Field string
+	// Expected describes the type of the field, e.g. "a boolean"
+	Expected string
+	Value    interface{}
+	// Coerced is the converted value, nil when the value was rejected or kept
+	Coerced interface{}
+	// Kept is set when lenient coercion could not convert the value, which stays an extra entry
+	Kept bool
+}
+
+// TypedField is a board field decoded only from values of its type, a value of another type ends up in the extra entries
+type TypedField struct {
+	Field string
+	// Expected describes the type of the field, e.g. "a boolean"
+	Expected string
+	valid    func(value interface{}) bool
+	convert  func(value interface{}) (interface{}, bool)
+}
+
+var TypedFields = []TypedField{
+	{Field: "core", Expected: "a string", valid: isString, convert: toCore},
+	{Field: "has_wifi", Expected: "a boolean", valid: isBool, convert: toBool},
+}
+
+func isString(value interface{}) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+func isBool(value interface{}) bool {
+	_, ok := value.(bool)
+	return ok
+}
+
+// Spreadsheets and vendor exports commonly spell booleans these ways, matched case insensitively
+var truthyValues = map[string]bool{
+	"true": true, "yes": true, "y": true, "on": true, "1": true,
+	"false": false, "no": false, "n": false, "off": false, "0": false,
+}
+
+func toBool(value interface{}) (interface{}, bool) {
+	if text, ok := value.(string); ok {
+		converted, exists := truthyValues[strings.ToLower(strings.TrimSpace(text))]
+		return converted, exists
+	}
+
+	if number, ok := toNumber(value); ok && (number == 0 || number == 1) {
+		return number == 1, true
+	}
+	return nil, false
+}
+
+// Numeric cores such as 8051 are kept with their exact notation
+func toCore(value interface{}) (interface{}, bool) {
+	switch typed := value.(type) {
+	case json.Number:
+		return typed.String(), true
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(typed), true
+	}
+	return nil, false
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch typed := value.(type) {
+	case json.Number:
+		number, err := typed.Float64()
+		return number, err == nil
+	case float64:
+		return typed, true
+	case int:
+		return float64(typed), true
+	}
+	return 0, false
+}
+
+// Apply converts or rejects in place the mistyped values of the typed fields of a board object, and returns them in field order
+func (coercion Coercion) Apply(object map[string]interface{}) []CoercedValue {
+	if !coercion.Enabled() {
+		return nil
+	}
+
+	var coerced []CoercedValue
+	for _, typed := range TypedFields {
+		value, exists := object[typed.Field]
+		if !exists || typed.valid(value) {
+			continue
+		}
+
+		result := CoercedValue{Field: typed.Field, Expected: typed.Expected, Value: value}
+		if converted, ok := typed.convert(value); ok && coercion == LenientCoercion {
+			object[typed.Field] = converted
+			result.Coerced = converted
+		} else if coercion == StrictCoercion {
+			delete(object, typed.Field)
+		} else {
+			// Lenient coercion keeps values it can't convert, as if coercion was off
+			result.Kept = true
+		}
+		coerced = append(coerced, result)
+	}
+	return coerced
+}
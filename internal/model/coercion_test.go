@@ -0,0 +1,77 @@
+package model_test
+
+import (
+	"boards-merger/internal/model"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCoercionApply(t *testing.T) {
+	tests := []struct {
+		name           string
+		coercion       model.Coercion
+		object         map[string]interface{}
+		expectedObject map[string]interface{}
+		expected       []model.CoercedValue
+	}{
+		{
+			name:           "Off keeps mistyped values",
+			coercion:       model.NoCoercion,
+			object:         map[string]interface{}{"has_wifi": "yes", "core": 8051.0},
+			expectedObject: map[string]interface{}{"has_wifi": "yes", "core": 8051.0},
+		},
+		{
+			name:           "Lenient converts known values",
+			coercion:       model.LenientCoercion,
+			object:         map[string]interface{}{"has_wifi": " Y ", "core": json.Number("8051"), "ram": "yes"},
+			expectedObject: map[string]interface{}{"has_wifi": true, "core": "8051", "ram": "yes"},
+			expected: []model.CoercedValue{
+				{Field: "core", Expected: "a string", Value: json.Number("8051"), Coerced: "8051"},
+				{Field: "has_wifi", Expected: "a boolean", Value: " Y ", Coerced: true},
+			},
+		},
+		{
+			name:           "Lenient converts numbers to booleans",
+			coercion:       model.LenientCoercion,
+			object:         map[string]interface{}{"has_wifi": 0.0},
+			expectedObject: map[string]interface{}{"has_wifi": false},
+			expected:       []model.CoercedValue{{Field: "has_wifi", Expected: "a boolean", Value: 0.0, Coerced: false}},
+		},
+		{
+			name:           "Lenient keeps unknown values",
+			coercion:       model.LenientCoercion,
+			object:         map[string]interface{}{"has_wifi": "maybe", "core": true},
+			expectedObject: map[string]interface{}{"has_wifi": "maybe", "core": true},
+			expected: []model.CoercedValue{
+				{Field: "core", Expected: "a string", Value: true, Kept: true},
+				{Field: "has_wifi", Expected: "a boolean", Value: "maybe", Kept: true},
+			},
+		},
+		{
+			name:           "Strict rejects mistyped values",
+			coercion:       model.StrictCoercion,
+			object:         map[string]interface{}{"has_wifi": "yes", "core": "CoreX"},
+			expectedObject: map[string]interface{}{"core": "CoreX"},
+			expected:       []model.CoercedValue{{Field: "has_wifi", Expected: "a boolean", Value: "yes"}},
+		},
+		{
+			name:           "Well typed values are untouched",
+			coercion:       model.StrictCoercion,
+			object:         map[string]interface{}{"has_wifi": false, "core": "CoreX"},
+			expectedObject: map[string]interface{}{"has_wifi": false, "core": "CoreX"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			coerced := test.coercion.Apply(test.object)
+			if !reflect.DeepEqual(test.object, test.expectedObject) {
+				t.Fatalf("Unexpected object: got %v, expected %v", test.object, test.expectedObject)
+			}
+			if !reflect.DeepEqual(coerced, test.expected) {
+				t.Fatalf("Unexpected coerced values: got %v, expected %v", coerced, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldChange is a field whose value differs between two catalogs, a missing value is nil
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type BoardChange struct {
+	Board  string        `json:"board"`
+	Name   string        `json:"name"`
+	Vendor string        `json:"vendor"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// BoardsDiff lists the boards added, removed and modified between an old and a new catalog
+type BoardsDiff struct {
+	Added   []Board       `json:"added"`
+	Removed []Board       `json:"removed"`
+	Changed []BoardChange `json:"changed"`
+}
+
+func (diff BoardsDiff) Empty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0
+}
+
+// Summary counts the differences, e.g. "2 added, 0 removed, 1 changed"
+func (diff BoardsDiff) Summary() string {
+	return fmt.Sprintf("%v added, %v removed, %v changed", len(diff.Added), len(diff.Removed), len(diff.Changed))
+}
+
+// Details lists every difference on its own line: '+' for added, '-' for removed and '~' for changed boards
+func (diff BoardsDiff) Details() []string {
+	var lines []string
+	for _, board := range diff.Added {
+		lines = append(lines, fmt.Sprintf("+ %v (%v)", board.Name, board.Vendor))
+	}
+	for _, board := range diff.Removed {
+		lines = append(lines, fmt.Sprintf("- %v (%v)", board.Name, board.Vendor))
+	}
+	for _, change := range diff.Changed {
+		fields := make([]string, 0, len(change.Fields))
+		for _, field := range change.Fields {
+			fields = append(fields, fmt.Sprintf("%v: %v -> %v", field.Field, formatDiffValue(field.Old), formatDiffValue(field.New)))
+		}
+		lines = append(lines, fmt.Sprintf("~ %v (%v): %v", change.Name, change.Vendor, strings.Join(fields, ", ")))
+	}
+	return lines
+}
+
+func formatDiffValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Fields returns the optional fields present in the board, keyed as in the JSON output
+func (board Board) Fields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(board.ExtraEntries)+2)
+	for key, value := range board.ExtraEntries {
+		fields[key] = value
+	}
+
+	if board.Core != "" {
+		fields["core"] = board.Core
+	}
+
+	if board.HasWiFi != nil {
+		fields["has_wifi"] = *board.HasWiFi
+	}
+
+	return fields
+}
+
+// DiffFields compares the optional fields of two versions of a board, in sorted field order
+func DiffFields(before Board, after Board) []FieldChange {
+	oldFields, newFields := before.Fields(), after.Fields()
+
+	union := make(map[string]interface{}, len(oldFields)+len(newFields))
+	for key := range oldFields {
+		union[key] = nil
+	}
+	for key := range newFields {
+		union[key] = nil
+	}
+
+	var changes []FieldChange
+	for _, key := range sortedEntryKeys(union) {
+		if isConflict(oldFields[key], newFields[key]) {
+			changes = append(changes, FieldChange{Field: key, Old: oldFields[key], New: newFields[key]})
+		}
+	}
+	return changes
+}
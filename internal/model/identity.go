@@ -0,0 +1,112 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IdentityKey is the ordered list of fields identifying a board, entries sharing the values of every field are the same
+// board. Fields are board fields or extra properties such as "revision", the zero value identifies boards by vendor and name.
+type IdentityKey []string
+
+var DefaultIdentity = IdentityKey{"vendor", "name"}
+
+// ParseIdentityKey parses a comma separated list of fields, e.g. "vendor,name,revision".
+// The vendor and name are always part of the identity, since entries of a board are merged into a single board.
+func ParseIdentityKey(spec string) (IdentityKey, error) {
+	var identity IdentityKey
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if len(field) == 0 {
+			continue
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("field '%v' is listed twice in identity key '%v'", field, spec)
+		}
+		seen[field] = true
+		identity = append(identity, field)
+	}
+
+	if !seen["vendor"] || !seen["name"] {
+		return nil, fmt.Errorf("identity key '%v' must contain the 'vendor' and 'name' fields", spec)
+	}
+	return identity, nil
+}
+
+func (identity IdentityKey) fields() IdentityKey {
+	if len(identity) == 0 {
+		return DefaultIdentity
+	}
+	return identity
+}
+
+// Key encodes the values of the identity fields of the board as a JSON array, so vendor "A::B" with name "C" and vendor "A"
+// with name "B::C" are different boards whatever characters they contain, and so are the revisions 1 and "1".
+// A missing field has a null value, so boards without a "revision" are a single board.
+func (identity IdentityKey) Key(board Board) string {
+	fields := identity.fields()
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		values[i] = board.rawFieldValue(field)
+	}
+
+	key, err := json.Marshal(values)
+	if err != nil {
+		// Decoded values are always encodable, the readable values are a fallback
+		return fmt.Sprintf("%q", identity.values(board))
+	}
+	return string(key)
+}
+
+// Label is the readable form of the identity of the board reported in conflicts and diffs, e.g. "Espressif::ESP32-DevKitC::B"
+func (identity IdentityKey) Label(board Board) string {
+	return strings.Join(identity.values(board), "::")
+}
+
+func (identity IdentityKey) values(board Board) []string {
+	fields := identity.fields()
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		values[i] = board.fieldValue(field)
+	}
+	return values
+}
+
+// rawFieldValue keeps the type of the extra properties, unlike fieldValue
+func (board Board) rawFieldValue(field string) interface{} {
+	switch field {
+	case "name", "vendor", "core":
+		return board.fieldValue(field)
+	case "has_wifi":
+		return board.HasWiFi
+	}
+	return board.ExtraEntries[field]
+}
+
+func (board Board) fieldValue(field string) string {
+	switch field {
+	case "name":
+		return board.Name
+	case "vendor":
+		return board.Vendor
+	case "core":
+		return board.Core
+	case "has_wifi":
+		if board.HasWiFi == nil {
+			return ""
+		}
+		return strconv.FormatBool(*board.HasWiFi)
+	}
+
+	if value, exists := board.ExtraEntries[field]; exists && value != nil {
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+func (identity IdentityKey) String() string {
+	return strings.Join(identity.fields(), ",")
+}
@@ -0,0 +1,111 @@
+package model_test
+
+import (
+	"boards-merger/internal/model"
+	"boards-merger/internal/utils/testutils"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseIdentityKey(t *testing.T) {
+	tests := []struct {
+		spec             string
+		expectedErr      bool
+		expectedIdentity model.IdentityKey
+	}{
+		{spec: "vendor,name", expectedIdentity: model.IdentityKey{"vendor", "name"}},
+		{spec: " name , vendor,revision ", expectedIdentity: model.IdentityKey{"name", "vendor", "revision"}},
+		{spec: "vendor,name,core,has_wifi", expectedIdentity: model.IdentityKey{"vendor", "name", "core", "has_wifi"}},
+		{spec: "name,revision", expectedErr: true},
+		{spec: "vendor,name,vendor", expectedErr: true},
+		{spec: "", expectedErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			identity, err := model.ParseIdentityKey(test.spec)
+			if test.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+			if !reflect.DeepEqual(identity, test.expectedIdentity) {
+				t.Fatalf("Unexpected identity: got %v, expected %v", identity, test.expectedIdentity)
+			}
+		})
+	}
+}
+
+func TestIdentityKey(t *testing.T) {
+	board := model.Board{Name: "Board1", Vendor: "VendorA", HasWiFi: &testutils.BoolTrue, ExtraEntries: map[string]interface{}{"revision": 2.0}}
+
+	tests := []struct {
+		identity      model.IdentityKey
+		expectedLabel string
+	}{
+		{identity: nil, expectedLabel: "VendorA::Board1"},
+		{identity: model.IdentityKey{"name", "vendor", "revision"}, expectedLabel: "Board1::VendorA::2"},
+		{identity: model.IdentityKey{"vendor", "name", "core", "has_wifi", "sku"}, expectedLabel: "VendorA::Board1::::true::"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.identity.String(), func(t *testing.T) {
+			if label := test.identity.Label(board); label != test.expectedLabel {
+				t.Fatalf("Unexpected label: got %v, expected %v", label, test.expectedLabel)
+			}
+		})
+	}
+
+	// Values containing the label separator never share a key
+	first := model.Board{Name: "C", Vendor: "A::B"}
+	second := model.Board{Name: "B::C", Vendor: "A"}
+	if model.DefaultIdentity.Key(first) == model.DefaultIdentity.Key(second) {
+		t.Fatalf("Unexpected key collision: %q", model.DefaultIdentity.Key(first))
+	}
+}
+
+func TestIdentityKeyDistinctValues(t *testing.T) {
+	identity := model.IdentityKey{"vendor", "name", "revision"}
+
+	tests := []struct {
+		name   string
+		first  model.Board
+		second model.Board
+	}{
+		{
+			name:   "NUL characters",
+			first:  model.Board{Name: "B\x00C", Vendor: "A"},
+			second: model.Board{Name: "C", Vendor: "A\x00B"},
+		},
+		{
+			name:   "Number and string",
+			first:  model.Board{Name: "Board1", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"revision": json.Number("1")}},
+			second: model.Board{Name: "Board1", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"revision": "1"}},
+		},
+		{
+			name:   "Missing and empty",
+			first:  model.Board{Name: "Board1", Vendor: "VendorA"},
+			second: model.Board{Name: "Board1", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"revision": ""}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if identity.Key(test.first) == identity.Key(test.second) {
+				t.Fatalf("Unexpected key collision: %q", identity.Key(test.first))
+			}
+		})
+	}
+
+	// Numbers are the same revision whichever decoder read them
+	first := model.Board{Name: "Board1", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"revision": json.Number("2")}}
+	second := model.Board{Name: "Board1", Vendor: "VendorA", ExtraEntries: map[string]interface{}{"revision": 2.0}}
+	if identity.Key(first) != identity.Key(second) {
+		t.Fatalf("Unexpected distinct keys: %q and %q", identity.Key(first), identity.Key(second))
+	}
+}
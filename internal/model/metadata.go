@@ -0,0 +1,70 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// ExtendedMetaData describes the merged catalog beyond its board & vendor counts, it is only computed on request
+type ExtendedMetaData struct {
+	// BoardsPerVendor counts the boards of every vendor
+	BoardsPerVendor map[string]int `json:"boards_per_vendor"`
+	// Cores counts the boards of every core, boards without core are not counted
+	Cores map[string]int `json:"cores"`
+	WiFi  WiFiCounts     `json:"wifi"`
+	Files FileCounts     `json:"files"`
+	// MergedDuplicates counts the entries merged into another entry of the same board, including near-duplicates
+	MergedDuplicates int       `json:"merged_duplicates"`
+	GeneratedAt      time.Time `json:"generated_at"`
+	ToolVersion      string    `json:"tool_version"`
+	// ContentHash is the SHA-256 of the JSON board list without sources, e.g. "sha256:9f86d0...",
+	// so catalogs holding the same boards in the same order share the same hash
+	ContentHash string `json:"content_hash"`
+}
+
+type WiFiCounts struct {
+	With    int `json:"with"`
+	Without int `json:"without"`
+	Unknown int `json:"unknown"`
+}
+
+// FileCounts counts the board files found, and how many were merged or skipped on read, parse or schema errors
+type FileCounts struct {
+	Scanned int `json:"scanned"`
+	Parsed  int `json:"parsed"`
+	Skipped int `json:"skipped"`
+}
+
+// recompute returns a copy of the metadata with the statistics of the boards computed again,
+// the file counts, merged duplicates, generation time and version are kept
+func (extended ExtendedMetaData) recompute(boards []Board) *ExtendedMetaData {
+	extended.BoardsPerVendor = make(map[string]int)
+	extended.Cores = make(map[string]int)
+	extended.WiFi = WiFiCounts{}
+
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+	for _, board := range boards {
+		extended.BoardsPerVendor[board.Vendor]++
+		if board.Core != "" {
+			extended.Cores[board.Core]++
+		}
+
+		switch {
+		case board.HasWiFi == nil:
+			extended.WiFi.Unknown++
+		case *board.HasWiFi:
+			extended.WiFi.With++
+		default:
+			extended.WiFi.Without++
+		}
+
+		board.Sources, board.ExtraSources = nil, nil
+		encoder.Encode(board)
+	}
+
+	extended.ContentHash = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	return &extended
+}
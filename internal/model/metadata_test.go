@@ -0,0 +1,70 @@
+package model_test
+
+import (
+	"boards-merger/internal/model"
+	"boards-merger/internal/utils/testutils"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtendedMetaData(t *testing.T) {
+	boardsInfo := &model.BoardsInfo{
+		Boards: []model.Board{
+			{Name: "Board1", Vendor: "VendorA", Core: "CoreX", HasWiFi: &testutils.BoolTrue, Sources: map[string]model.Source{"name": {File: "a.json"}}},
+			{Name: "Board2", Vendor: "VendorA", Core: "CoreX", HasWiFi: &testutils.BoolFalse},
+			{Name: "Board3", Vendor: "VendorB"},
+		},
+	}
+
+	boardsInfo.UpdateMetaData()
+	data, err := json.Marshal(boardsInfo.MetaData)
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+	if string(data) != `{"unique_vendors":2,"total_boards":3}` {
+		t.Fatalf("Unexpected metadata without extended metadata: %s", data)
+	}
+
+	boardsInfo.MetaData.ExtendedMetaData = &model.ExtendedMetaData{Files: model.FileCounts{Scanned: 3, Parsed: 2, Skipped: 1}, MergedDuplicates: 4, ToolVersion: "v1"}
+	boardsInfo.UpdateMetaData()
+	extended := boardsInfo.MetaData.ExtendedMetaData
+
+	if !reflect.DeepEqual(extended.BoardsPerVendor, map[string]int{"VendorA": 2, "VendorB": 1}) {
+		t.Fatalf("Unexpected boards per vendor: %v", extended.BoardsPerVendor)
+	}
+	if !reflect.DeepEqual(extended.Cores, map[string]int{"CoreX": 2}) {
+		t.Fatalf("Unexpected cores: %v", extended.Cores)
+	}
+	if extended.WiFi != (model.WiFiCounts{With: 1, Without: 1, Unknown: 1}) {
+		t.Fatalf("Unexpected WiFi counts: %v", extended.WiFi)
+	}
+	if extended.Files != (model.FileCounts{Scanned: 3, Parsed: 2, Skipped: 1}) || extended.MergedDuplicates != 4 || extended.ToolVersion != "v1" {
+		t.Fatalf("Unexpected kept metadata: %+v", extended)
+	}
+	if !strings.HasPrefix(extended.ContentHash, "sha256:") || len(extended.ContentHash) != len("sha256:")+64 {
+		t.Fatalf("Unexpected content hash: %v", extended.ContentHash)
+	}
+
+	// Sources are not part of the content, a different board list is
+	withoutSources := *boardsInfo
+	withoutSources.Boards = append([]model.Board(nil), boardsInfo.Boards...)
+	withoutSources.Boards[0].Sources = nil
+	withoutSources.UpdateMetaData()
+	if withoutSources.MetaData.ContentHash != extended.ContentHash {
+		t.Fatalf("Unexpected content hash change without sources: %v", withoutSources.MetaData.ContentHash)
+	}
+
+	filtered := boardsInfo.Filter(func(board model.Board) bool { return board.Vendor == "VendorA" })
+	filteredExtended := filtered.MetaData.ExtendedMetaData
+	if filteredExtended == nil || !reflect.DeepEqual(filteredExtended.BoardsPerVendor, map[string]int{"VendorA": 2}) || filteredExtended.WiFi.Unknown != 0 {
+		t.Fatalf("Unexpected filtered metadata: %+v", filteredExtended)
+	}
+	if filteredExtended.Files != extended.Files || filteredExtended.ContentHash == extended.ContentHash {
+		t.Fatalf("Unexpected filtered metadata: %+v", filteredExtended)
+	}
+	if !reflect.DeepEqual(boardsInfo.MetaData.BoardsPerVendor, map[string]int{"VendorA": 2, "VendorB": 1}) {
+		t.Fatalf("Unexpected change of the unfiltered metadata: %v", boardsInfo.MetaData.BoardsPerVendor)
+	}
+}
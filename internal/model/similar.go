@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NearDuplicate is a pair of boards of the same vendor whose names are probably the same board
+type NearDuplicate struct {
+	Vendor string    `json:"vendor"`
+	Names  [2]string `json:"names"`
+	// Score is the similarity of the names, from 0 to 1 where 1 means equal once normalized
+	Score float64 `json:"score"`
+	// Merged is set when the second board was merged into the first one
+	Merged bool `json:"merged"`
+}
+
+// NameSimilarity compares two board names once normalized (e.g. "ESP32-DevKitC" and "esp32 devkitc" are equal),
+// as 1 minus their edit distance relative to the longest name, rounded to two decimals
+func NameSimilarity(a string, b string) float64 {
+	runesA, runesB := []rune(NormalizedName(a)), []rune(NormalizedName(b))
+	longest := max(len(runesA), len(runesB))
+	if longest == 0 {
+		return 0
+	}
+
+	score := 1 - float64(editDistance(runesA, runesB))/float64(longest)
+	return math.Round(score*100) / 100
+}
+
+// editDistance is the Levenshtein distance, computed with a single row of the distance matrix
+func editDistance(a []rune, b []rune) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			above := row[j]
+			row[j] = min(row[j]+1, row[j-1]+1, diagonal+cost)
+			diagonal = above
+		}
+	}
+	return row[len(b)]
+}
+
+// BoardEquivalences lists the names of the same board per vendor, curated by hand.
+// Names match regardless of case, spaces and punctuation, like vendors in the VendorRegistry.
+type BoardEquivalences struct {
+	// names maps a vendor and a normalized name to the first name of its group
+	names map[[2]string]string
+}
+
+// NewBoardEquivalences builds the equivalences from groups of names per vendor, the first name of a group is the name
+// kept for the merged board, e.g. {"Espressif": [["ESP32-DevKitC", "ESP32 DevKitC V4"]]}
+func NewBoardEquivalences(vendors map[string][][]string) (*BoardEquivalences, error) {
+	equivalences := &BoardEquivalences{names: make(map[[2]string]string)}
+
+	vendorNames := make([]string, 0, len(vendors))
+	for vendor := range vendors {
+		vendorNames = append(vendorNames, vendor)
+	}
+	sort.Strings(vendorNames)
+
+	for _, vendor := range vendorNames {
+		for _, group := range vendors[vendor] {
+			if len(group) < 2 {
+				return nil, fmt.Errorf("equivalent boards of '%v' must be listed by groups of at least two names, got %v", vendor, group)
+			}
+
+			for _, name := range group {
+				key := [2]string{vendor, NormalizedName(name)}
+				if len(key[1]) == 0 {
+					return nil, fmt.Errorf("board name '%v' of '%v' has no letters or digits", name, vendor)
+				}
+				if existing, exists := equivalences.names[key]; exists && existing != group[0] {
+					return nil, fmt.Errorf("board '%v' of '%v' is equivalent to both '%v' and '%v'", name, vendor, existing, group[0])
+				}
+				equivalences.names[key] = group[0]
+			}
+		}
+	}
+
+	return equivalences, nil
+}
+
+// Canonical returns the name kept for a board listed in an equivalence group
+func (equivalences *BoardEquivalences) Canonical(vendor string, name string) (string, bool) {
+	if equivalences == nil {
+		return "", false
+	}
+	canonical, exists := equivalences.names[[2]string{vendor, NormalizedName(name)}]
+	return canonical, exists
+}
@@ -0,0 +1,56 @@
+package model_test
+
+import (
+	"boards-merger/internal/model"
+	"testing"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a             string
+		b             string
+		expectedScore float64
+	}{
+		{a: "ESP32-DevKitC", b: "ESP32 DevKitC", expectedScore: 1},
+		{a: "ESP32-DevKitC", b: "esp32_devkitc", expectedScore: 1},
+		{a: "ESP32-DevKitC", b: "ESP32-DevKitC-1", expectedScore: 0.92},
+		{a: "Nucleo-F401RE", b: "Nucleo-F411RE", expectedScore: 0.92},
+		{a: "Arduino Uno", b: "Raspberry Pi Pico", expectedScore: 0.2},
+		{a: "---", b: "***", expectedScore: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+" "+test.b, func(t *testing.T) {
+			if score := model.NameSimilarity(test.a, test.b); score != test.expectedScore {
+				t.Fatalf("Unexpected score: got %v, expected %v", score, test.expectedScore)
+			}
+		})
+	}
+}
+
+func TestBoardEquivalences(t *testing.T) {
+	equivalences, err := model.NewBoardEquivalences(map[string][][]string{
+		"Espressif": {{"ESP32-DevKitC", "ESP32 DevKitC V4", "ESP32-DevKitC-32E"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	if canonical, listed := equivalences.Canonical("Espressif", "esp32 devkitc-32e"); !listed || canonical != "ESP32-DevKitC" {
+		t.Fatalf("Unexpected canonical name: '%v' (%v)", canonical, listed)
+	}
+	if _, listed := equivalences.Canonical("Nordic", "ESP32 DevKitC V4"); listed {
+		t.Fatalf("Unexpected equivalence of another vendor")
+	}
+
+	invalid := []map[string][][]string{
+		{"Espressif": {{"ESP32-DevKitC"}}},
+		{"Espressif": {{"ESP32-DevKitC", "--"}}},
+		{"Espressif": {{"ESP32-DevKitC", "ESP32 DevKitC V4"}, {"ESP32-S3", "ESP32 DevKitC V4"}}},
+	}
+	for _, vendors := range invalid {
+		if _, err := model.NewBoardEquivalences(vendors); err == nil {
+			t.Fatalf("Expected an error for %v", vendors)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey orders boards by a board field or an extra property, e.g. "revision"
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// SortOrder is the ordered list of keys sorting the merged boards, ties are broken by the identity of the boards.
+// The zero value sorts boards by their identity fields only.
+type SortOrder []SortKey
+
+// ParseSortOrder parses a comma separated list of fields, each optionally followed by ':asc' or ':desc',
+// e.g. "vendor,name:desc"
+func ParseSortOrder(spec string) (SortOrder, error) {
+	var order SortOrder
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(spec, ",") {
+		key = strings.TrimSpace(key)
+		if len(key) == 0 {
+			continue
+		}
+
+		field, direction, _ := strings.Cut(key, ":")
+		field = strings.TrimSpace(field)
+		if len(field) == 0 {
+			return nil, fmt.Errorf("missing field in sort key '%v'", key)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("field '%v' is listed twice in sort order '%v'", field, spec)
+		}
+		seen[field] = true
+
+		sortKey := SortKey{Field: field}
+		switch strings.ToLower(strings.TrimSpace(direction)) {
+		case "", "asc":
+		case "desc":
+			sortKey.Descending = true
+		default:
+			return nil, fmt.Errorf("unknown sort direction '%v' of field '%v', expected 'asc' or 'desc'", direction, field)
+		}
+		order = append(order, sortKey)
+	}
+	return order, nil
+}
+
+func (order SortOrder) String() string {
+	keys := make([]string, len(order))
+	for i, key := range order {
+		keys[i] = key.Field
+		if key.Descending {
+			keys[i] += ":desc"
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// Sort orders the boards by the keys of the order, comparing values regardless of case and numbers by their value,
+// e.g. "ESP32-S2" before "esp32-S10". Boards without a value for a key come last, whatever its direction.
+// Boards equal on every key are ordered by their identity fields, compared one at a time and byte-wise.
+func (order SortOrder) Sort(boards []Board, identity IdentityKey) {
+	sort.SliceStable(boards, func(i int, j int) bool {
+		for _, key := range order {
+			a, b := boards[i].fieldValue(key.Field), boards[j].fieldValue(key.Field)
+			if len(a) == 0 || len(b) == 0 {
+				if len(a) != len(b) {
+					return len(b) == 0
+				}
+				continue
+			}
+			if c := naturalCompare(a, b); c != 0 {
+				return (c < 0) != key.Descending
+			}
+		}
+
+		for _, field := range identity.fields() {
+			if c := strings.Compare(boards[i].fieldValue(field), boards[j].fieldValue(field)); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// naturalCompare compares strings regardless of case, with runs of digits compared by their numeric value
+func naturalCompare(a string, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for len(a) > 0 && len(b) > 0 {
+		chunkA, chunkB := leadingChunk(a), leadingChunk(b)
+		if c := compareChunks(chunkA, chunkB); c != 0 {
+			return c
+		}
+		a, b = a[len(chunkA):], b[len(chunkB):]
+	}
+	return len(a) - len(b)
+}
+
+// leadingChunk returns the leading run of digits, or of other characters, of a non-empty string
+func leadingChunk(s string) string {
+	digits := isDigit(s[0])
+	end := 1
+	for end < len(s) && isDigit(s[end]) == digits {
+		end++
+	}
+	return s[:end]
+}
+
+func compareChunks(a string, b string) int {
+	if !isDigit(a[0]) || !isDigit(b[0]) {
+		return strings.Compare(a, b)
+	}
+
+	// Numbers of any length are compared without leading zeros, a longer number is greater
+	trimmedA, trimmedB := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(trimmedA) != len(trimmedB) {
+		return len(trimmedA) - len(trimmedB)
+	}
+	if c := strings.Compare(trimmedA, trimmedB); c != 0 {
+		return c
+	}
+	return len(a) - len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
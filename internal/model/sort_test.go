@@ -0,0 +1,97 @@
+package model_test
+
+import (
+	"boards-merger/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	tests := []struct {
+		spec          string
+		expectedErr   bool
+		expectedOrder model.SortOrder
+	}{
+		{spec: "", expectedOrder: nil},
+		{spec: "vendor, name:desc", expectedOrder: model.SortOrder{{Field: "vendor"}, {Field: "name", Descending: true}}},
+		{spec: "revision:ASC,has_wifi:Desc", expectedOrder: model.SortOrder{{Field: "revision"}, {Field: "has_wifi", Descending: true}}},
+		{spec: "name:up", expectedErr: true},
+		{spec: ":desc", expectedErr: true},
+		{spec: "name,name:desc", expectedErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			order, err := model.ParseSortOrder(test.spec)
+			if test.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+			if !reflect.DeepEqual(order, test.expectedOrder) {
+				t.Fatalf("Unexpected order: got %v, expected %v", order, test.expectedOrder)
+			}
+		})
+	}
+}
+
+func TestSortOrderSort(t *testing.T) {
+	boards := []model.Board{
+		{Name: "esp32-S10", Vendor: "Espressif", ExtraEntries: map[string]interface{}{"revision": 10.0}},
+		{Name: "Pico", Vendor: "Raspberry::Pi"},
+		{Name: "ESP32-S2", Vendor: "espressif", ExtraEntries: map[string]interface{}{"revision": 2.0}},
+		{Name: "Board", Vendor: "Raspberry", ExtraEntries: map[string]interface{}{"revision": "B"}},
+		{Name: "ESP32-S2", Vendor: "Espressif", ExtraEntries: map[string]interface{}{"revision": "02"}},
+	}
+
+	tests := []struct {
+		name           string
+		spec           string
+		expectedBoards []string
+	}{
+		{
+			name:           "Identity compared field by field and byte-wise",
+			spec:           "",
+			expectedBoards: []string{"Espressif/ESP32-S2", "Espressif/esp32-S10", "Raspberry/Board", "Raspberry::Pi/Pico", "espressif/ESP32-S2"},
+		},
+		{
+			name:           "Natural and case insensitive",
+			spec:           "name",
+			expectedBoards: []string{"Raspberry/Board", "Espressif/ESP32-S2", "espressif/ESP32-S2", "Espressif/esp32-S10", "Raspberry::Pi/Pico"},
+		},
+		{
+			name:           "Descending with missing values last",
+			spec:           "revision:desc,vendor",
+			expectedBoards: []string{"Raspberry/Board", "Espressif/esp32-S10", "Espressif/ESP32-S2", "espressif/ESP32-S2", "Raspberry::Pi/Pico"},
+		},
+		{
+			name:           "Vendor then name descending",
+			spec:           "vendor,name:desc",
+			expectedBoards: []string{"Espressif/esp32-S10", "Espressif/ESP32-S2", "espressif/ESP32-S2", "Raspberry/Board", "Raspberry::Pi/Pico"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, err := model.ParseSortOrder(test.spec)
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			sorted := append([]model.Board(nil), boards...)
+			order.Sort(sorted, nil)
+
+			var names []string
+			for _, board := range sorted {
+				names = append(names, board.Vendor+"/"+board.Name)
+			}
+			if !reflect.DeepEqual(names, test.expectedBoards) {
+				t.Fatalf("Unexpected order: got %v, expected %v", names, test.expectedBoards)
+			}
+		})
+	}
+}
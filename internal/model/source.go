@@ -0,0 +1,71 @@
+package model
+
+// Source locates a board entry: the file it was read from and its index within that file's boards list
+type Source struct {
+	File  string `json:"file"`
+	Index int    `json:"index"`
+}
+
+type Candidate struct {
+	Value  interface{} `json:"value"`
+	Source Source      `json:"source"`
+}
+
+// Conflict describes a field on which duplicate entries of a board disagreed, and the value that was kept
+type Conflict struct {
+	Board      string      `json:"board"`
+	Name       string      `json:"name"`
+	Vendor     string      `json:"vendor"`
+	Field      string      `json:"field"`
+	Candidates []Candidate `json:"candidates"`
+	Chosen     interface{} `json:"chosen"`
+}
+
+// SetSource records source as the origin of every field and extra entry present in the board
+func (board *Board) SetSource(source Source) {
+	board.Sources = map[string]Source{
+		"name":   source,
+		"vendor": source,
+	}
+
+	if board.Core != "" {
+		board.Sources["core"] = source
+	}
+
+	if board.HasWiFi != nil {
+		board.Sources["has_wifi"] = source
+	}
+
+	board.ExtraSources = make(map[string]Source, len(board.ExtraEntries))
+	for key := range board.ExtraEntries {
+		board.ExtraSources[key] = source
+	}
+}
+
+// RenameSourceFiles replaces the file of every source and conflict candidate by its rename, e.g. to hide the directory
+// the files were read from. The source maps are copied, boards of a filtered catalog share them with the unfiltered one.
+func (boardinfo *BoardsInfo) RenameSourceFiles(rename func(file string) string) {
+	renameAll := func(sources map[string]Source) map[string]Source {
+		if sources == nil {
+			return nil
+		}
+		renamed := make(map[string]Source, len(sources))
+		for key, source := range sources {
+			renamed[key] = Source{File: rename(source.File), Index: source.Index}
+		}
+		return renamed
+	}
+
+	for i := range boardinfo.Boards {
+		boardinfo.Boards[i].Sources = renameAll(boardinfo.Boards[i].Sources)
+		boardinfo.Boards[i].ExtraSources = renameAll(boardinfo.Boards[i].ExtraSources)
+	}
+
+	for i, conflict := range boardinfo.Conflicts {
+		candidates := make([]Candidate, len(conflict.Candidates))
+		for j, candidate := range conflict.Candidates {
+			candidates[j] = Candidate{Value: candidate.Value, Source: Source{File: rename(candidate.Source.File), Index: candidate.Source.Index}}
+		}
+		boardinfo.Conflicts[i].Candidates = candidates
+	}
+}
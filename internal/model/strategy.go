@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var ErrConflict = errors.New("conflicting board values")
+
+// Resolution decides which value is kept when two entries of the same board disagree on a field
+type Resolution string
+
+const (
+	FirstWins      Resolution = "first-wins"
+	LastWins       Resolution = "last-wins"
+	FailOnConflict Resolution = "fail-on-conflict"
+	PreferNonEmpty Resolution = "prefer-non-empty"
+)
+
+var Resolutions = []Resolution{FirstWins, LastWins, FailOnConflict, PreferNonEmpty}
+
+func parseResolution(value string) (Resolution, error) {
+	for _, resolution := range Resolutions {
+		if string(resolution) == value {
+			return resolution, nil
+		}
+	}
+	return "", fmt.Errorf("unknown conflict resolution '%v'", value)
+}
+
+// MergeStrategy holds a default resolution and per-field overrides, fields are 'core', 'has_wifi' or any extra property key.
+// The zero value resolves every field with LastWins.
+type MergeStrategy struct {
+	Default Resolution
+	Fields  map[string]Resolution
+}
+
+// ParseMergeStrategy parses a comma separated list of a default resolution and 'field=resolution' overrides,
+// e.g. "first-wins,core=fail-on-conflict,has_wifi=prefer-non-empty"
+func ParseMergeStrategy(spec string) (MergeStrategy, error) {
+	var strategy MergeStrategy
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+
+		field, value, isOverride := strings.Cut(part, "=")
+		if !isOverride {
+			value = part
+		}
+		resolution, err := parseResolution(strings.TrimSpace(value))
+		if err != nil {
+			return MergeStrategy{}, err
+		}
+
+		if !isOverride {
+			if strategy.Default != "" {
+				return MergeStrategy{}, fmt.Errorf("multiple default resolutions in strategy '%v'", spec)
+			}
+			strategy.Default = resolution
+			continue
+		}
+
+		field = strings.TrimSpace(field)
+		if len(field) == 0 || field == "name" || field == "vendor" {
+			return MergeStrategy{}, fmt.Errorf("invalid strategy field '%v'", field)
+		}
+		if strategy.Fields == nil {
+			strategy.Fields = make(map[string]Resolution)
+		}
+		strategy.Fields[field] = resolution
+	}
+
+	return strategy, nil
+}
+
+func (strategy MergeStrategy) String() string {
+	parts := []string{string(strategy.For(""))}
+	for field, resolution := range strategy.Fields {
+		parts = append(parts, field+"="+string(resolution))
+	}
+	sort.Strings(parts[1:])
+	return strings.Join(parts, ",")
+}
+
+func (strategy MergeStrategy) For(field string) Resolution {
+	if resolution, exists := strategy.Fields[field]; exists {
+		return resolution
+	}
+	if strategy.Default == "" {
+		return LastWins
+	}
+	return strategy.Default
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch typed := value.(type) {
+	case nil:
+		return true
+	case string:
+		return len(strings.TrimSpace(typed)) == 0
+	case []interface{}:
+		return len(typed) == 0
+	case map[string]interface{}:
+		return len(typed) == 0
+	default:
+		return false
+	}
+}
+
+// resolve picks between the current value (read first) and the incoming value (read last) of a conflicting field
+func (strategy MergeStrategy) resolve(field string, current interface{}, incoming interface{}) (interface{}, error) {
+	switch strategy.For(field) {
+	case FirstWins:
+		return current, nil
+	case FailOnConflict:
+		return nil, fmt.Errorf("%w for '%v': {'%v', '%v'}", ErrConflict, field, current, incoming)
+	case PreferNonEmpty:
+		// An empty value never overrides a non-empty one, otherwise the latest value wins
+		if isEmptyValue(incoming) && !isEmptyValue(current) {
+			return current, nil
+		}
+		return incoming, nil
+	default:
+		return incoming, nil
+	}
+}
+
+func isConflict(current interface{}, incoming interface{}) bool {
+	return !reflect.DeepEqual(current, incoming)
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// VendorRegistry maps vendor aliases, and the case & punctuation variants of vendor names, to canonical vendor names
+type VendorRegistry struct {
+	vendors map[string]string
+}
+
+// NormalizedName keeps only the lowercased letters & digits of a name, so "Espressif Systems" matches "espressif-systems"
+func NormalizedName(name string) string {
+	var key strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			key.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return key.String()
+}
+
+// NewVendorRegistry builds a registry from canonical vendor names and their aliases,
+// e.g. {"Espressif": ["Espressif Systems", "Espressif Inc."]}
+func NewVendorRegistry(vendors map[string][]string) (*VendorRegistry, error) {
+	registry := &VendorRegistry{vendors: make(map[string]string)}
+
+	canonicals := make([]string, 0, len(vendors))
+	for canonical := range vendors {
+		canonicals = append(canonicals, canonical)
+	}
+	sort.Strings(canonicals)
+
+	for _, canonical := range canonicals {
+		if len(strings.TrimSpace(canonical)) == 0 {
+			return nil, fmt.Errorf("empty canonical vendor name")
+		}
+
+		for _, name := range append([]string{canonical}, vendors[canonical]...) {
+			key := NormalizedName(name)
+			if len(key) == 0 {
+				return nil, fmt.Errorf("vendor alias '%v' of '%v' has no letters or digits", name, canonical)
+			}
+			if existing, exists := registry.vendors[key]; exists && existing != canonical {
+				return nil, fmt.Errorf("vendor alias '%v' matches both '%v' and '%v'", name, existing, canonical)
+			}
+			registry.vendors[key] = canonical
+		}
+	}
+
+	return registry, nil
+}
+
+// Canonical returns the canonical name of a vendor, and whether it matched a registry entry
+func (registry *VendorRegistry) Canonical(vendor string) (string, bool) {
+	if canonical, exists := registry.vendors[NormalizedName(vendor)]; exists {
+		return canonical, true
+	}
+	return vendor, false
+}
@@ -0,0 +1,58 @@
+package model_test
+
+import (
+	"boards-merger/internal/model"
+	"testing"
+)
+
+func TestVendorRegistryCanonical(t *testing.T) {
+	registry, err := model.NewVendorRegistry(map[string][]string{
+		"Espressif":          {"Espressif Systems", "Espressif Inc."},
+		"STMicroelectronics": {"ST", "ST Micro"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	tests := []struct {
+		vendor            string
+		expectedCanonical string
+		expectedMatch     bool
+	}{
+		{vendor: "Espressif", expectedCanonical: "Espressif", expectedMatch: true},
+		{vendor: "espressif", expectedCanonical: "Espressif", expectedMatch: true},
+		{vendor: "Espressif Systems", expectedCanonical: "Espressif", expectedMatch: true},
+		{vendor: "ESPRESSIF-SYSTEMS", expectedCanonical: "Espressif", expectedMatch: true},
+		{vendor: "espressif inc", expectedCanonical: "Espressif", expectedMatch: true},
+		{vendor: "St.", expectedCanonical: "STMicroelectronics", expectedMatch: true},
+		{vendor: "Nordic", expectedCanonical: "Nordic", expectedMatch: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.vendor, func(t *testing.T) {
+			canonical, matched := registry.Canonical(test.vendor)
+			if canonical != test.expectedCanonical || matched != test.expectedMatch {
+				t.Fatalf("Unexpected canonical vendor: got '%v' (%v), expected '%v' (%v)", canonical, matched, test.expectedCanonical, test.expectedMatch)
+			}
+		})
+	}
+}
+
+func TestNewVendorRegistryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		vendors map[string][]string
+	}{
+		{name: "Empty canonical name", vendors: map[string][]string{" ": {"Espressif"}}},
+		{name: "Alias without letters or digits", vendors: map[string][]string{"Espressif": {"--"}}},
+		{name: "Alias of two vendors", vendors: map[string][]string{"Espressif": {"ESP"}, "Esp": {}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := model.NewVendorRegistry(test.vendors); err == nil {
+				t.Fatalf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenField
+	tokenString
+	tokenNumber
+	tokenKeyword
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// value is the unquoted string, the float64 number or the keyword value (true, false or nil for null)
+	value interface{}
+	// column is the 1-based position of the token in the expression
+	column int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEnd {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%v'", t.text)
+}
+
+// Longer operators are listed first, so "==" is never read as "=" followed by "="
+var operators = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!", "(", ")"}
+
+var keywords = map[string]interface{}{"true": true, "false": false, "null": nil}
+
+// tokenize splits the expression into tokens, ending with a tokenEnd token
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(expression) {
+		c := expression[i]
+		column := i + 1
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"' || c == '\'':
+			text, value, err := readString(expression[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at column %v", err.Error(), column)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, value: value, column: column})
+			i += len(text)
+
+		case isDigit(c) || (c == '-' && i+1 < len(expression) && isDigit(expression[i+1])):
+			end := i + 1
+			for end < len(expression) && (isDigit(expression[end]) || expression[end] == '.') {
+				end++
+			}
+			number, err := strconv.ParseFloat(expression[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number '%v' at column %v", expression[i:end], column)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: expression[i:end], value: number, column: column})
+			i = end
+
+		case isFieldStart(c):
+			end := i + 1
+			for end < len(expression) && isFieldPart(expression[end]) {
+				end++
+			}
+			text := expression[i:end]
+			if value, exists := keywords[text]; exists {
+				tokens = append(tokens, token{kind: tokenKeyword, text: text, value: value, column: column})
+			} else {
+				tokens = append(tokens, token{kind: tokenField, text: text, column: column})
+			}
+			i = end
+
+		default:
+			operator := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(expression[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character '%c' at column %v", c, column)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, column: column})
+			i += len(operator)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, column: len(expression) + 1}), nil
+}
+
+// readString reads a string quoted with " or ' at the start of s, returning its quoted text and its value.
+// A backslash only escapes the quote and another backslash, so regular expressions such as "ESP32\.S2" keep their escapes.
+func readString(s string) (string, string, error) {
+	quote := s[0]
+	var value strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == quote || s[i+1] == '\\'):
+			value.WriteByte(s[i+1])
+			i++
+		case s[i] == quote:
+			return s[:i+1], value.String(), nil
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isFieldStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Extra property keys such as "flash-size" or "usb.type" are valid field names
+func isFieldPart(c byte) bool {
+	return isFieldStart(c) || isDigit(c) || c == '-' || c == '.'
+}
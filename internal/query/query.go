@@ -0,0 +1,350 @@
+package query
+
+import (
+	"boards-merger/internal/model"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Expression is a parsed filter expression over the fields and extra properties of a board, e.g.
+// vendor == "Espressif" && has_wifi && core =~ "ESP32.*"
+//
+// Operands are fields, "double" or 'single' quoted strings, numbers, true, false and null, combined with
+// ==, !=, <, <=, >, >=, =~ (matches a regular expression), !~, !, && and || in decreasing precedence, and parentheses.
+// A field alone is true when it is set and not false, "" or 0, a missing field equals null.
+type Expression struct {
+	source string
+	root   condition
+}
+
+// Limits of a filter expression, so untrusted input such as a web query can't exhaust the parser's stack
+const (
+	MaxLength  = 4096
+	MaxNesting = 64
+)
+
+// Parse compiles a filter expression, errors locate the offending token by its column
+func Parse(expression string) (*Expression, error) {
+	if len(expression) > MaxLength {
+		return nil, fmt.Errorf("invalid filter expression: longer than %v characters", MaxLength)
+	}
+
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEnd {
+		err = p.unexpected()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+	return &Expression{source: expression, root: root}, nil
+}
+
+// Match reports whether the board satisfies the expression
+func (expression *Expression) Match(board model.Board) bool {
+	return expression.root.match(board)
+}
+
+func (expression *Expression) String() string {
+	return expression.source
+}
+
+type parser struct {
+	tokens []token
+	next   int
+	// depth counts the enclosing '!' and '(' of the current token
+	depth int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEnd {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) isOperator(operators ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, operator := range operators {
+		if t.text == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	return fmt.Errorf("unexpected %v at column %v", t, t.column)
+}
+
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.isOperator("||") {
+		p.take()
+		var right condition
+		if right, err = p.parseAnd(); err == nil {
+			left = or{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.isOperator("&&") {
+		p.take()
+		var right condition
+		if right, err = p.parseUnary(); err == nil {
+			left = and{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseUnary() (condition, error) {
+	if !p.isOperator("!", "(") {
+		return p.parseComparison()
+	}
+
+	if p.depth == MaxNesting {
+		return nil, fmt.Errorf("expression nested deeper than %v levels at column %v", MaxNesting, p.peek().column)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	if p.isOperator("!") {
+		p.take()
+		operand, err := p.parseUnary()
+		return not{operand}, err
+	}
+
+	p.take()
+	inner, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator(")") {
+		return nil, p.unexpected()
+	}
+	p.take()
+	return inner, nil
+}
+
+func (p *parser) parseComparison() (condition, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("==", "!=", "<", "<=", ">", ">=", "=~", "!~") {
+		return truthy{left}, nil
+	}
+
+	operator := p.take()
+	patternToken := p.peek()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if operator.text != "=~" && operator.text != "!~" {
+		return comparison{operator: operator.text, left: left, right: right}, nil
+	}
+
+	// Regular expressions are compiled once, so they must be string literals
+	if patternToken.kind != tokenString {
+		return nil, fmt.Errorf("expected a quoted regular expression after '%v' at column %v", operator.text, patternToken.column)
+	}
+	pattern, err := regexp.Compile(patternToken.value.(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression at column %v: %v", patternToken.column, err.Error())
+	}
+	return match{left: left, pattern: pattern, negated: operator.text == "!~"}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenField:
+		p.take()
+		return field(t.text), nil
+	case tokenString, tokenNumber, tokenKeyword:
+		p.take()
+		return literal{t.value}, nil
+	}
+	return nil, p.unexpected()
+}
+
+type condition interface {
+	match(board model.Board) bool
+}
+
+type and struct{ left, right condition }
+
+func (c and) match(board model.Board) bool { return c.left.match(board) && c.right.match(board) }
+
+type or struct{ left, right condition }
+
+func (c or) match(board model.Board) bool { return c.left.match(board) || c.right.match(board) }
+
+type not struct{ operand condition }
+
+func (c not) match(board model.Board) bool { return !c.operand.match(board) }
+
+type truthy struct{ operand operand }
+
+func (c truthy) match(board model.Board) bool {
+	switch value := c.operand.value(board).(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case string:
+		return value != ""
+	case float64:
+		return value != 0
+	}
+	return true
+}
+
+type comparison struct {
+	operator    string
+	left, right operand
+}
+
+func (c comparison) match(board model.Board) bool {
+	left, right := c.left.value(board), c.right.value(board)
+	switch c.operator {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+
+	// Only numbers and strings are ordered, any other pair of values never matches
+	order := 0
+	switch left := left.(type) {
+	case float64:
+		right, ok := right.(float64)
+		if !ok {
+			return false
+		}
+		if left < right {
+			order = -1
+		} else if left > right {
+			order = 1
+		}
+	case string:
+		right, ok := right.(string)
+		if !ok {
+			return false
+		}
+		if left < right {
+			order = -1
+		} else if left > right {
+			order = 1
+		}
+	default:
+		return false
+	}
+
+	switch c.operator {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
+
+// equal compares values of the same type, lists and objects of extra properties are never equal to a literal
+func equal(left interface{}, right interface{}) bool {
+	switch left := left.(type) {
+	case nil, bool, string, float64:
+		return left == right
+	}
+	return false
+}
+
+type match struct {
+	left    operand
+	pattern *regexp.Regexp
+	negated bool
+}
+
+// Numbers and booleans are matched as they are written, a missing field never matches
+func (c match) match(board model.Board) bool {
+	var text string
+	switch value := c.left.value(board).(type) {
+	case string:
+		text = value
+	case float64:
+		text = strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		text = strconv.FormatBool(value)
+	default:
+		return c.negated
+	}
+	return c.pattern.MatchString(text) != c.negated
+}
+
+type operand interface {
+	value(board model.Board) interface{}
+}
+
+type literal struct{ v interface{} }
+
+func (l literal) value(model.Board) interface{} { return l.v }
+
+// field is a board field or an extra property, its value is nil when missing
+type field string
+
+func (f field) value(board model.Board) interface{} {
+	switch f {
+	case "name":
+		return board.Name
+	case "vendor":
+		return board.Vendor
+	case "core":
+		if board.Core == "" {
+			return nil
+		}
+		return board.Core
+	case "has_wifi":
+		if board.HasWiFi == nil {
+			return nil
+		}
+		return *board.HasWiFi
+	}
+
+	switch value := board.ExtraEntries[string(f)].(type) {
+	case json.Number:
+		number, err := value.Float64()
+		if err != nil {
+			return value.String()
+		}
+		return number
+	case int:
+		return float64(value)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,118 @@
+package query_test
+
+import (
+	"boards-merger/internal/model"
+	"boards-merger/internal/query"
+	"boards-merger/internal/utils/testutils"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExpressionMatch(t *testing.T) {
+	boards := []model.Board{
+		{Name: "ESP32-DevKitC", Vendor: "Espressif", Core: "ESP32", HasWiFi: &testutils.BoolTrue, ExtraEntries: map[string]interface{}{"pins": 38.0, "flash-size": "4MB"}},
+		{Name: "ESP8266 NodeMCU", Vendor: "Espressif", Core: "ESP8266", HasWiFi: &testutils.BoolTrue, ExtraEntries: map[string]interface{}{"pins": json.Number("30")}},
+		{Name: "Arduino Uno", Vendor: "Arduino", Core: "ATmega328P", HasWiFi: &testutils.BoolFalse, ExtraEntries: map[string]interface{}{"pins": 20.0, "ports": []interface{}{"usb"}}},
+		{Name: "Raspberry Pi Pico", Vendor: "Raspberry Pi"},
+	}
+
+	tests := []struct {
+		expression     string
+		expectedBoards []string
+	}{
+		{expression: `vendor == "Espressif" && has_wifi && core =~ "ESP32.*"`, expectedBoards: []string{"ESP32-DevKitC"}},
+		{expression: `vendor == 'Espressif' || !core`, expectedBoards: []string{"ESP32-DevKitC", "ESP8266 NodeMCU", "Raspberry Pi Pico"}},
+		{expression: `has_wifi == false`, expectedBoards: []string{"Arduino Uno"}},
+		{expression: `has_wifi == null && core == null`, expectedBoards: []string{"Raspberry Pi Pico"}},
+		{expression: `pins >= 30 && pins < 38.5`, expectedBoards: []string{"ESP32-DevKitC", "ESP8266 NodeMCU"}},
+		{expression: `pins > "20"`, expectedBoards: nil},
+		{expression: `name < "B"`, expectedBoards: []string{"Arduino Uno"}},
+		{expression: `flash-size == "4MB"`, expectedBoards: []string{"ESP32-DevKitC"}},
+		{expression: `pins =~ "^3"`, expectedBoards: []string{"ESP32-DevKitC", "ESP8266 NodeMCU"}},
+		{expression: `core !~ "(?i)^esp"`, expectedBoards: []string{"Arduino Uno", "Raspberry Pi Pico"}},
+		{expression: `name =~ "Pi\.*"`, expectedBoards: []string{"Raspberry Pi Pico"}},
+		{expression: `!(vendor == "Espressif" || vendor == "Arduino") `, expectedBoards: []string{"Raspberry Pi Pico"}},
+		{expression: `vendor == "Espressif" && (core == "ESP8266" || pins == 20)`, expectedBoards: []string{"ESP8266 NodeMCU"}},
+		{expression: `ports`, expectedBoards: []string{"Arduino Uno"}},
+		{expression: `ports == "usb"`, expectedBoards: nil},
+		{expression: `true`, expectedBoards: []string{"ESP32-DevKitC", "ESP8266 NodeMCU", "Arduino Uno", "Raspberry Pi Pico"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			expression, err := query.Parse(test.expression)
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			var names []string
+			for _, board := range boards {
+				if expression.Match(board) {
+					names = append(names, board.Name)
+				}
+			}
+			if strings.Join(names, ",") != strings.Join(test.expectedBoards, ",") {
+				t.Fatalf("Unexpected boards: got %v, expected %v", names, test.expectedBoards)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expression  string
+		expectedErr string
+	}{
+		{expression: `vendor == `, expectedErr: "unexpected end of expression at column 11"},
+		{expression: `vendor = "Espressif"`, expectedErr: "unexpected character '=' at column 8"},
+		{expression: `vendor == "Espressif`, expectedErr: "unterminated string at column 11"},
+		{expression: `(has_wifi && core`, expectedErr: "unexpected end of expression at column 18"},
+		{expression: `has_wifi core`, expectedErr: "unexpected 'core' at column 10"},
+		{expression: `core =~ name`, expectedErr: "expected a quoted regular expression after '=~' at column 9"},
+		{expression: `core =~ "(ESP"`, expectedErr: "invalid regular expression at column 9"},
+		{expression: `&& has_wifi`, expectedErr: "unexpected '&&' at column 1"},
+		{expression: ``, expectedErr: "unexpected end of expression at column 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := query.Parse(test.expression)
+			if err == nil {
+				t.Fatalf("Expected an error")
+			}
+			if !strings.Contains(err.Error(), test.expectedErr) {
+				t.Fatalf("Unexpected err: got %v, expected %v", err.Error(), test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		expectedErr string
+	}{
+		{name: "Nesting limit", expression: strings.Repeat("!", query.MaxNesting) + "has_wifi"},
+		{name: "Nested negations", expression: strings.Repeat("!", query.MaxNesting+1) + "has_wifi", expectedErr: "expression nested deeper than 64 levels at column 65"},
+		{name: "Nested parentheses", expression: strings.Repeat("(", 100) + "has_wifi" + strings.Repeat(")", 100), expectedErr: "expression nested deeper than 64 levels at column 65"},
+		{name: "Mixed nesting", expression: strings.Repeat("!(", 40) + "has_wifi" + strings.Repeat(")", 40), expectedErr: "expression nested deeper than 64 levels at column 65"},
+		{name: "Too long", expression: strings.Repeat("!", 3000000), expectedErr: "longer than 4096 characters"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := query.Parse(test.expression)
+			if len(test.expectedErr) == 0 {
+				if err != nil {
+					t.Fatalf("Unexpected err: %v", err.Error())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+				t.Fatalf("Unexpected err: got %v, expected %v", err, test.expectedErr)
+			}
+		})
+	}
+}
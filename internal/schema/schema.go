@@ -0,0 +1,170 @@
+package schema
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Builtin is the JSON Schema of the accepted board files
+//
+//go:embed boards.schema.json
+var Builtin []byte
+
+const builtinURL = "https://github.com/aosama16/Boards-Listing-tool/boards.schema.json"
+
+// Violation is a value that does not match the schema, located by its JSON pointer within the file
+type Violation struct {
+	Pointer string
+	Message string
+}
+
+func (violation Violation) String() string {
+	pointer := violation.Pointer
+	if len(pointer) == 0 {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%v: %v", pointer, violation.Message)
+}
+
+// BoardIndex returns the index of the board the violation belongs to, -1 when it is not within the 'boards' list
+func (violation Violation) BoardIndex() int {
+	rest, found := strings.CutPrefix(violation.Pointer, "/boards/")
+	if !found {
+		return -1
+	}
+	index, err := strconv.Atoi(strings.SplitN(rest, "/", 2)[0])
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+// Validator checks board files against the built-in schema, and every board against an optional extension schema
+type Validator struct {
+	builtin   *jsonschema.Schema
+	extension *jsonschema.Schema
+}
+
+// New compiles the built-in schema, extensionPath is an optional schema applied to every board, e.g. to require or type extra properties
+func New(extensionPath string) (*Validator, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(builtinURL, bytes.NewReader(Builtin)); err != nil {
+		return nil, fmt.Errorf("invalid built-in schema: %v", err.Error())
+	}
+
+	var validator Validator
+	var err error
+	if validator.builtin, err = compiler.Compile(builtinURL); err != nil {
+		return nil, fmt.Errorf("invalid built-in schema: %v", err.Error())
+	}
+
+	if len(extensionPath) == 0 {
+		return &validator, nil
+	}
+
+	absPath, err := filepath.Abs(extensionPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema path %v: %v", extensionPath, err.Error())
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %v", err.Error())
+	}
+	if err := compiler.AddResource(absPath, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("invalid schema %v: %v", extensionPath, err.Error())
+	}
+	if validator.extension, err = compiler.Compile(absPath); err != nil {
+		return nil, fmt.Errorf("invalid schema %v: %v", extensionPath, err.Error())
+	}
+
+	return &validator, nil
+}
+
+// Validate checks a decoded JSON document, as produced by json.Unmarshal
+func (validator *Validator) Validate(document interface{}) []Violation {
+	violations := collectViolations(validator.builtin.Validate(document), "")
+	if validator.extension == nil {
+		return sortViolations(violations)
+	}
+
+	// The extension applies to every board object, whether the document is a list of boards or a single board
+	object, isObject := document.(map[string]interface{})
+	if !isObject {
+		return sortViolations(violations)
+	}
+	boards, isList := object["boards"].([]interface{})
+	if !isList {
+		return sortViolations(append(violations, collectViolations(validator.extension.Validate(object), "")...))
+	}
+	for i, board := range boards {
+		if _, isObject := board.(map[string]interface{}); isObject {
+			violations = append(violations, collectViolations(validator.extension.Validate(board), fmt.Sprintf("/boards/%d", i))...)
+		}
+	}
+	return sortViolations(violations)
+}
+
+// ValidateBoard checks a single board object located at pointer, e.g. a CSV row
+func (validator *Validator) ValidateBoard(board map[string]interface{}, pointer string) []Violation {
+	violations := collectViolations(validator.builtin.Validate(board), pointer)
+	if validator.extension != nil {
+		violations = append(violations, collectViolations(validator.extension.Validate(board), pointer)...)
+	}
+	return sortViolations(violations)
+}
+
+// collectViolations flattens the validation error into its leaf causes, which carry the most specific messages
+func collectViolations(err error, pointer string) []Violation {
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []Violation{{Pointer: pointer, Message: err.Error()}}
+	}
+
+	var violations []Violation
+	seen := make(map[Violation]struct{})
+	var walk func(cause *jsonschema.ValidationError)
+	walk = func(cause *jsonschema.ValidationError) {
+		if len(cause.Causes) > 0 {
+			for _, nested := range cause.Causes {
+				walk(nested)
+			}
+			return
+		}
+
+		violation := Violation{Pointer: pointer + cause.InstanceLocation, Message: cause.Message}
+		if _, exists := seen[violation]; !exists {
+			seen[violation] = struct{}{}
+			violations = append(violations, violation)
+		}
+	}
+	walk(validationErr)
+	return violations
+}
+
+// sortViolations orders the violations by board, the validator reports them in map order
+func sortViolations(violations []Violation) []Violation {
+	sort.SliceStable(violations, func(i, j int) bool {
+		first, second := violations[i], violations[j]
+		if first.BoardIndex() != second.BoardIndex() {
+			return first.BoardIndex() < second.BoardIndex()
+		}
+		if first.Pointer != second.Pointer {
+			return first.Pointer < second.Pointer
+		}
+		return first.Message < second.Message
+	})
+	return violations
+}
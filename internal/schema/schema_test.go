@@ -0,0 +1,103 @@
+package schema_test
+
+import (
+	"boards-merger/internal/schema"
+	"boards-merger/internal/utils/testutils"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	extensionPath := filepath.Join(dir, "extension.json")
+	testutils.WriteToFile(t, extensionPath, `{"type": "object", "required": ["ram"], "properties": {"ram": {"type": "integer"}}}`)
+
+	tests := []struct {
+		name               string
+		extension          string
+		document           string
+		expectedViolations []schema.Violation
+	}{
+		{
+			name:     "Valid boards list",
+			document: `{"boards": [{"name": "Board1", "vendor": "VendorA", "core": "CoreX", "has_wifi": true, "ram": "512KB"}]}`,
+		},
+		{
+			name:     "Valid single board",
+			document: `{"name": "Board1", "vendor": "VendorA"}`,
+		},
+		{
+			name:     "Invalid boards",
+			document: `{"boards": [{"name": "Board1", "vendor": "VendorA", "has_wifi": "yes"}, {"name": "", "vendor": "VendorA", "core": 5}]}`,
+			expectedViolations: []schema.Violation{
+				{Pointer: "/boards/0/has_wifi", Message: "expected boolean, but got string"},
+				{Pointer: "/boards/1/core", Message: "expected string, but got number"},
+				{Pointer: "/boards/1/name", Message: "does not match pattern '\\\\S'"},
+				{Pointer: "/boards/1/name", Message: "length must be >= 1, but got 0"},
+			},
+		},
+		{
+			name:     "Whitespace only name and vendor",
+			document: `{"name": " ", "vendor": "\t"}`,
+			expectedViolations: []schema.Violation{
+				{Pointer: "/name", Message: "does not match pattern '\\\\S'"},
+				{Pointer: "/vendor", Message: "does not match pattern '\\\\S'"},
+			},
+		},
+		{
+			name:     "Invalid single board",
+			document: `{"name": "Board1"}`,
+			expectedViolations: []schema.Violation{
+				{Pointer: "", Message: "missing properties: 'vendor'"},
+			},
+		},
+		{
+			name:     "Empty boards list",
+			document: `{"boards": []}`,
+			expectedViolations: []schema.Violation{
+				{Pointer: "/boards", Message: "minimum 1 items required, but found 0 items"},
+			},
+		},
+		{
+			name:      "Extension schema",
+			extension: extensionPath,
+			document:  `{"boards": [{"name": "Board1", "vendor": "VendorA", "ram": 512}, {"name": "Board2", "vendor": "VendorA", "ram": "512KB"}, {"name": "Board3", "vendor": "VendorA"}]}`,
+			expectedViolations: []schema.Violation{
+				{Pointer: "/boards/1/ram", Message: "expected integer, but got string"},
+				{Pointer: "/boards/2", Message: "missing properties: 'ram'"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator, err := schema.New(test.extension)
+			if err != nil {
+				t.Fatalf("Unexpected err: %v", err.Error())
+			}
+
+			var document interface{}
+			if err := json.Unmarshal([]byte(test.document), &document); err != nil {
+				t.Fatalf("Invalid test document: %v", err.Error())
+			}
+
+			violations := validator.Validate(document)
+			if !reflect.DeepEqual(violations, test.expectedViolations) {
+				t.Fatalf("Unexpected violations: got %v, expected %v", violations, test.expectedViolations)
+			}
+		})
+	}
+
+	if index := (schema.Violation{Pointer: "/boards/12/core"}).BoardIndex(); index != 12 {
+		t.Fatalf("Unexpected board index: got %v, expected 12", index)
+	}
+
+	if _, err := schema.New(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatalf("Expected an error for a missing extension schema")
+	}
+}
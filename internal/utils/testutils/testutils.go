@@ -13,7 +13,7 @@ var (
 	BoolFalse = false
 )
 
-func WriteToFile(t *testing.T, filePath string, data string) {
+func WriteToFile(t testing.TB, filePath string, data string) {
 	t.Helper()
 	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE, 0644)
 	if err != nil {
@@ -26,7 +26,7 @@ func WriteToFile(t *testing.T, filePath string, data string) {
 	}
 }
 
-func CreateTempDir(t *testing.T) string {
+func CreateTempDir(t testing.TB) string {
 	t.Helper()
 	dir, err := os.MkdirTemp("", "testdir")
 	if err != nil {
@@ -0,0 +1,126 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileState identifies a version of a file or directory, a change of either field marks it as modified
+type FileState struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// Snapshot maps watched paths to their state, paths that no longer exist are left out
+type Snapshot map[string]FileState
+
+func Take(paths []string) Snapshot {
+	snapshot := make(Snapshot, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		snapshot[path] = FileState{ModTime: info.ModTime(), Size: info.Size()}
+	}
+	return snapshot
+}
+
+func (snapshot Snapshot) Equal(other Snapshot) bool {
+	if len(snapshot) != len(other) {
+		return false
+	}
+	for path, state := range snapshot {
+		if otherState, exists := other[path]; !exists || !otherState.ModTime.Equal(state.ModTime) || otherState.Size != state.Size {
+			return false
+		}
+	}
+	return true
+}
+
+// Paths returns the files and every directory between them and the root, directories change when entries are added or removed.
+// Files added to a directory without any watched file are only noticed when the directory is watched, see Directories.
+func Paths(root string, files []string) []string {
+	root = filepath.Clean(root)
+	seen := map[string]struct{}{root: {}}
+	paths := []string{root}
+	for _, file := range files {
+		for path := file; path != root && !isRoot(path); path = filepath.Dir(path) {
+			if _, exists := seen[path]; exists {
+				break
+			}
+			seen[path] = struct{}{}
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Directories walks the root and returns it with every directory below it down to maxDepth levels, unreadable directories
+// and symbolic links are skipped. Walking again on every poll notices files added to a new subdirectory.
+func Directories(root string, maxDepth int) []string {
+	root = filepath.Clean(root)
+	rootDepth := strings.Count(filepath.ToSlash(root), "/")
+	var directories []string
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if strings.Count(filepath.ToSlash(path), "/")-rootDepth > maxDepth {
+			return filepath.SkipDir
+		}
+		directories = append(directories, path)
+		return nil
+	})
+	return directories
+}
+
+func isRoot(path string) bool {
+	return filepath.Dir(path) == path
+}
+
+// Poller detects changes by comparing snapshots, it works on any file system without OS notification support
+type Poller struct {
+	// Interval between two snapshots
+	Interval time.Duration
+	// Debounce is how long the snapshot must stay unchanged before a change is reported, so bursts of writes trigger a single run
+	Debounce time.Duration
+	// Ticks triggers the snapshots instead of a ticker of Interval when set, e.g. to poll at chosen times in tests
+	Ticks <-chan time.Time
+}
+
+// Run polls the paths until ctx is cancelled, calling onChange once per settled change.
+// paths is queried again after every onChange call, so the watched set can follow the directory contents.
+func (poller Poller) Run(ctx context.Context, paths func() []string, onChange func()) error {
+	ticks := poller.Ticks
+	if ticks == nil {
+		ticker := time.NewTicker(poller.Interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	last := Take(paths())
+	pending := false
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticks:
+			current := Take(paths())
+			if !current.Equal(last) {
+				last, pending, changedAt = current, true, now
+				continue
+			}
+
+			if pending && now.Sub(changedAt) >= poller.Debounce {
+				pending = false
+				onChange()
+				last = Take(paths())
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package watch_test
+
+import (
+	"boards-merger/internal/utils/testutils"
+	"boards-merger/internal/watch"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPaths(t *testing.T) {
+	root := filepath.FromSlash("/boards")
+	files := []string{
+		filepath.FromSlash("/boards/a.json"),
+		filepath.FromSlash("/boards/vendor/b.json"),
+		filepath.FromSlash("/boards/vendor/c.json"),
+		filepath.FromSlash("/boards/vendor/sub/d.json"),
+	}
+
+	expected := []string{
+		filepath.FromSlash("/boards"),
+		filepath.FromSlash("/boards/a.json"),
+		filepath.FromSlash("/boards/vendor/b.json"),
+		filepath.FromSlash("/boards/vendor"),
+		filepath.FromSlash("/boards/vendor/c.json"),
+		filepath.FromSlash("/boards/vendor/sub/d.json"),
+		filepath.FromSlash("/boards/vendor/sub"),
+	}
+	if paths := watch.Paths(root, files); !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("Unexpected paths: got %v, expected %v", paths, expected)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{"name": "Board1", "vendor": "VendorA"}`)
+	paths := []string{dir, filePath, filepath.Join(dir, "missing.json")}
+
+	before := watch.Take(paths)
+	if len(before) != 2 {
+		t.Fatalf("Unexpected snapshot: got %v, expected the directory and the file", before)
+	}
+	if !before.Equal(watch.Take(paths)) {
+		t.Fatalf("Unexpected change without modifications")
+	}
+
+	testutils.WriteToFile(t, filePath, `{"name": "Board1", "vendor": "VendorA", "core": "CoreX"}`)
+	if before.Equal(watch.Take(paths)) {
+		t.Fatalf("Expected a change after modifying the file")
+	}
+}
+
+func TestDirectories(t *testing.T) {
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	nested := filepath.Join(dir, "vendor", "sub")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+	testutils.WriteToFile(t, filepath.Join(dir, "vendor", "boards.json"), `{}`)
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		expected []string
+	}{
+		{name: "Root only", maxDepth: 0, expected: []string{dir}},
+		{name: "One level", maxDepth: 1, expected: []string{dir, filepath.Join(dir, "vendor")}},
+		{name: "Every level", maxDepth: 10, expected: []string{dir, filepath.Join(dir, "vendor"), nested}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if directories := watch.Directories(dir, test.maxDepth); !reflect.DeepEqual(directories, test.expected) {
+				t.Fatalf("Unexpected directories: got %v, expected %v", directories, test.expected)
+			}
+		})
+	}
+}
+
+func TestDirectoriesNewSubdirectory(t *testing.T) {
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{}`)
+	subdir := filepath.Join(dir, "vendor")
+	if err := os.Mkdir(subdir, 0755); err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+
+	// The new subdirectory holds no known file yet, it is watched through the directory walk
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(subdir, past, past); err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+	paths := func() []string { return append(watch.Paths(dir, []string{filePath}), watch.Directories(dir, 10)...) }
+	before := watch.Take(paths())
+	testutils.WriteToFile(t, filepath.Join(subdir, "boards-2.json"), `{"name": "Board2", "vendor": "VendorA"}`)
+	if before.Equal(watch.Take(paths())) {
+		t.Fatalf("Expected a change after adding a file to a new subdirectory")
+	}
+}
+
+func TestPollerDebounce(t *testing.T) {
+	dir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "boards-1.json")
+	testutils.WriteToFile(t, filePath, `{}`)
+
+	var changes atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A tick is sent twice, the second send returns once the first tick is handled
+	ticks := make(chan time.Time)
+	start := time.Now()
+	tick := func(at time.Duration) {
+		ticks <- start.Add(at)
+		ticks <- start.Add(at)
+	}
+
+	poller := watch.Poller{Debounce: 200 * time.Millisecond, Ticks: ticks}
+	done := make(chan error)
+	go func() {
+		done <- poller.Run(ctx, func() []string { return []string{filePath} }, func() { changes.Add(1) })
+	}()
+
+	// A burst of writes shorter than the debounce duration is reported once
+	tick(0)
+	for i := 0; i < 5; i++ {
+		testutils.WriteToFile(t, filePath, `{"name": "Board1", "vendor": "VendorA"}`+string(make([]byte, i+1)))
+		tick(time.Duration(i+1) * 20 * time.Millisecond)
+	}
+	tick(250 * time.Millisecond)
+	if count := changes.Load(); count != 0 {
+		t.Fatalf("Unexpected number of changes before the debounce duration: got %v, expected 0", count)
+	}
+	tick(300 * time.Millisecond)
+	tick(500 * time.Millisecond)
+	if count := changes.Load(); count != 1 {
+		t.Fatalf("Unexpected number of changes: got %v, expected 1", count)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Unexpected err: got %v, expected %v", err, context.Canceled)
+	}
+}
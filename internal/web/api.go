@@ -0,0 +1,235 @@
+package web
+
+import (
+	"boards-merger/internal/core"
+	"boards-merger/internal/encoders"
+	"boards-merger/internal/model"
+	"boards-merger/internal/query"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type apiError struct {
+	Error apiErrorBody `json:"error"`
+}
+
+type apiErrorBody struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type mergeRequest struct {
+	Root      string `json:"root"`
+	Path      string `json:"path"`
+	Recursive bool   `json:"recursive"`
+	Depth     *int   `json:"depth"`
+	Strategy  string `json:"strategy"`
+	Sources   bool   `json:"sources"`
+	// Where is a filter expression of the returned boards, e.g. 'vendor == "Espressif" && has_wifi'
+	Where string `json:"where"`
+}
+
+func (srv *server) registerApiRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v1/merge", srv.handleApiMerge)
+	mux.HandleFunc("GET /api/v1/boards", srv.handleApiBoards)
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		writeApiError(w, http.StatusNotFound, fmt.Sprintf("unknown API endpoint: %v %v", r.Method, r.URL.Path))
+	})
+}
+
+func writeApiError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiError{Error: apiErrorBody{Status: status, Message: message}})
+}
+
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, core.ErrInvalidPath), errors.Is(err, fs.ErrNotExist), errors.Is(err, ErrUnknownRoot):
+		return http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission), errors.Is(err, ErrOutsideRoot):
+		return http.StatusForbidden
+	case errors.Is(err, core.ErrNotDirectory):
+		return http.StatusBadRequest
+	case errors.Is(err, core.ErrNoFiles), errors.Is(err, core.ErrNoBoards):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, model.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeBoards encodes the result with the encoder selected by the 'format' query parameter (JSON by default)
+func writeBoards(w http.ResponseWriter, r *http.Request, boards *model.BoardsInfo) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	encoder, err := encoders.Get(format)
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", encoder.ContentType())
+	encoder.Encode(w, boards)
+}
+
+func (srv *server) mergeDirectory(w http.ResponseWriter, r *http.Request, request mergeRequest) (*model.BoardsInfo, bool) {
+	depth := 10
+	if request.Depth != nil {
+		if *request.Depth < 0 {
+			writeApiError(w, http.StatusBadRequest, fmt.Sprintf("invalid 'depth' value: %v", *request.Depth))
+			return nil, false
+		}
+		depth = *request.Depth
+	}
+
+	strategy, err := model.ParseMergeStrategy(request.Strategy)
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	root, path, err := srv.config.Roots.Resolve(request.Root, request.Path)
+	if err != nil {
+		writeApiError(w, statusForError(err), err.Error())
+		return nil, false
+	}
+
+	ctx, cancel := srv.requestContext(r)
+	defer cancel()
+
+	jsonList, err := core.ReadDirectoryContext(ctx, path, core.ReadOptions{Recursive: request.Recursive, MaxDepth: depth})
+	if err != nil {
+		writeApiError(w, statusForError(err), root.Hide(err.Error()))
+		return nil, false
+	}
+
+	boards, err := core.ProcessJsonFilesContext(ctx, jsonList, core.MergeOptions{Strategy: strategy, Provenance: request.Sources, Aliases: srv.config.Aliases, Coercion: srv.config.Coercion, Vendors: srv.config.Vendors, Identity: srv.config.Identity, Sort: srv.config.Sort, GroupByVendor: srv.config.GroupByVendor, ExtendedMetaData: srv.config.ExtendedMetaData, Similarity: srv.config.Similarity})
+	if err != nil {
+		writeApiError(w, statusForError(err), root.Hide(err.Error()))
+		return nil, false
+	}
+
+	// Sources name the files relative to the root, as '<root name>/<relative path>'
+	boards.RenameSourceFiles(root.Display)
+	return boards, true
+}
+
+func (srv *server) handleApiMerge(w http.ResponseWriter, r *http.Request) {
+	var request mergeRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeApiError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err.Error()))
+		return
+	}
+
+	where, err := parseWhere(request.Where)
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	boards, ok := srv.mergeDirectory(w, r, request)
+	if !ok {
+		return
+	}
+
+	writeBoards(w, r, boards.Filter(where))
+}
+
+func (srv *server) handleApiBoards(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := mergeRequest{Root: query.Get("root"), Path: query.Get("path"), Strategy: query.Get("strategy")}
+
+	if value := query.Get("recursive"); value != "" {
+		recursive, err := strconv.ParseBool(value)
+		if err != nil {
+			writeApiError(w, http.StatusBadRequest, fmt.Sprintf("invalid 'recursive' value: %v", value))
+			return
+		}
+		request.Recursive = recursive
+	}
+
+	if value := query.Get("sources"); value != "" {
+		sources, err := strconv.ParseBool(value)
+		if err != nil {
+			writeApiError(w, http.StatusBadRequest, fmt.Sprintf("invalid 'sources' value: %v", value))
+			return
+		}
+		request.Sources = sources
+	}
+
+	if value := query.Get("depth"); value != "" {
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 0 {
+			writeApiError(w, http.StatusBadRequest, fmt.Sprintf("invalid 'depth' value: %v", value))
+			return
+		}
+		request.Depth = &depth
+	}
+
+	keep, err := boardsFilter(query.Get("vendor"), query.Get("core"), query.Get("has_wifi"))
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	where, err := parseWhere(query.Get("where"))
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	boards, ok := srv.mergeDirectory(w, r, request)
+	if !ok {
+		return
+	}
+
+	writeBoards(w, r, boards.Filter(func(board model.Board) bool { return keep(board) && where(board) }))
+}
+
+// parseWhere compiles a filter expression, an empty expression matches every board
+func parseWhere(expression string) (func(model.Board) bool, error) {
+	if strings.TrimSpace(expression) == "" {
+		return func(model.Board) bool { return true }, nil
+	}
+	where, err := query.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	return where.Match, nil
+}
+
+// Empty filter values match every board, vendor and core are compared case insensitively
+func boardsFilter(vendor string, boardCore string, hasWiFi string) (func(model.Board) bool, error) {
+	matchWiFi := func(*bool) bool { return true }
+	switch strings.ToLower(hasWiFi) {
+	case "":
+	case "unknown":
+		matchWiFi = func(value *bool) bool { return value == nil }
+	default:
+		expected, err := strconv.ParseBool(hasWiFi)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'has_wifi' value: %v, expected true, false or unknown", hasWiFi)
+		}
+		matchWiFi = func(value *bool) bool { return value != nil && *value == expected }
+	}
+
+	return func(board model.Board) bool {
+		return (vendor == "" || strings.EqualFold(board.Vendor, vendor)) &&
+			(boardCore == "" || strings.EqualFold(board.Core, boardCore)) &&
+			matchWiFi(board.HasWiFi)
+	}, nil
+}
@@ -0,0 +1,339 @@
+package web_test
+
+import (
+	"boards-merger/internal/utils/logger"
+	"boards-merger/internal/utils/testutils"
+	"boards-merger/internal/web"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApi(t *testing.T) {
+	logger.Disable()
+
+	rootDir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(rootDir)
+	outsideDir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(outsideDir)
+
+	dir := filepath.Join(rootDir, "boards")
+	emptyDir := filepath.Join(rootDir, "empty")
+	conflictsDir := filepath.Join(rootDir, "conflicts")
+	for _, subDir := range []string{dir, emptyDir, conflictsDir} {
+		if err := os.Mkdir(subDir, 0755); err != nil {
+			t.Fatalf("failed to create subdirectory: %v", err)
+		}
+	}
+	testutils.WriteToFile(t, filepath.Join(dir, "boards-1.json"), `{
+		"boards": [
+			{"name": "Board1", "vendor": "VendorA", "core": "CoreX", "has_wifi": true},
+			{"name": "Board2", "vendor": "VendorA", "has_wifi": false},
+			{"name": "Board3", "vendor": "VendorB", "core": "CoreX"}
+		]
+	}`)
+	testutils.WriteToFile(t, filepath.Join(conflictsDir, "boards-1.json"), `{"name": "Board1", "vendor": "VendorA", "core": "CoreX"}`)
+	testutils.WriteToFile(t, filepath.Join(conflictsDir, "boards-2.json"), `{"name": "Board1", "vendor": "VendorA", "core": "CoreY"}`)
+	testutils.WriteToFile(t, filepath.Join(outsideDir, "boards-1.json"), `{"name": "Board4", "vendor": "VendorC"}`)
+	if err := os.Symlink(outsideDir, filepath.Join(rootDir, "link")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedBoards []string
+	}{
+		{
+			name:           "Merge directory",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"root": "boards-root", "path": "boards", "recursive": true, "depth": 2}`,
+			expectedStatus: http.StatusOK,
+			expectedBoards: []string{"Board1", "Board2", "Board3"},
+		},
+		{
+			name:           "Merge with invalid body",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": 1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Merge with unknown field",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "boards", "recurse": true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Merge invalid path",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "Invalid path"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Merge a file path",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "boards/boards-1.json"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Merge directory without board files",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "empty"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Merge absolute path inside the root",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "` + filepath.ToSlash(dir) + `"}`,
+			expectedStatus: http.StatusOK,
+			expectedBoards: []string{"Board1", "Board2", "Board3"},
+		},
+		{
+			name:           "Merge absolute path outside the root",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "` + filepath.ToSlash(outsideDir) + `"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Merge path escaping the root",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "boards/../../` + filepath.Base(outsideDir) + `"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Merge symbolic link pointing outside the root",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "link"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Merge unknown root",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"root": "other", "path": "boards"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Merge conflicting boards with first-wins strategy",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "conflicts", "strategy": "first-wins"}`,
+			expectedStatus: http.StatusOK,
+			expectedBoards: []string{"Board1"},
+		},
+		{
+			name:           "Merge directory with negative depth",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "boards", "recursive": true, "depth": -1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Merge conflicting boards with fail-on-conflict strategy",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "conflicts", "strategy": "fail-on-conflict"}`,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Merge with invalid strategy",
+			method:         http.MethodGet,
+			target:         "/api/v1/boards?path=conflicts&strategy=newest-wins",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Filter boards by vendor and core",
+			method:         http.MethodGet,
+			target:         "/api/v1/boards?vendor=vendora&core=CoreX&path=boards",
+			expectedStatus: http.StatusOK,
+			expectedBoards: []string{"Board1"},
+		},
+		{
+			name:           "Filter boards by unknown WiFi",
+			method:         http.MethodGet,
+			target:         "/api/v1/boards?has_wifi=unknown&path=boards",
+			expectedStatus: http.StatusOK,
+			expectedBoards: []string{"Board3"},
+		},
+		{
+			name:           "Filter boards with invalid has_wifi",
+			method:         http.MethodGet,
+			target:         "/api/v1/boards?has_wifi=maybe&path=boards",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Merge directory with a filter expression",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "boards", "where": "vendor == \"VendorA\" && !has_wifi"}`,
+			expectedStatus: http.StatusOK,
+			expectedBoards: []string{"Board2"},
+		},
+		{
+			name:           "Filter boards by core and expression",
+			method:         http.MethodGet,
+			target:         "/api/v1/boards?core=CoreX&where=" + url.QueryEscape(`has_wifi == null || name =~ "2$"`) + "&path=boards",
+			expectedStatus: http.StatusOK,
+			expectedBoards: []string{"Board3"},
+		},
+		{
+			name:           "Filter boards with invalid expression",
+			method:         http.MethodGet,
+			target:         "/api/v1/boards?where=" + url.QueryEscape(`vendor = "VendorA"`) + "&path=boards",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Merge directory with a deeply nested expression",
+			method:         http.MethodPost,
+			target:         "/api/v1/merge",
+			body:           `{"path": "boards", "where": "` + strings.Repeat("!", 100000) + `has_wifi"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Boards from the root directory",
+			method:         http.MethodGet,
+			target:         "/api/v1/boards?root=boards-root",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Unknown format",
+			method:         http.MethodGet,
+			target:         "/api/v1/boards?format=xml&path=boards",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown endpoint",
+			method:         http.MethodGet,
+			target:         "/api/v1/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	roots := web.Roots{{Name: "boards-root", Path: rootDir}}
+	if err := roots.Validate(); err != nil {
+		t.Fatalf("Unexpected roots validation err: %v", err.Error())
+	}
+	router := web.NewRouter(web.Config{Roots: roots}, rootDir)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("Unexpected status: got %v, expected %v, body: %v", recorder.Code, test.expectedStatus, recorder.Body.String())
+			}
+
+			if test.expectedStatus != http.StatusOK {
+				var envelope struct {
+					Error struct {
+						Status  int    `json:"status"`
+						Message string `json:"message"`
+					} `json:"error"`
+				}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+					t.Fatalf("Unexpected error envelope: %v", recorder.Body.String())
+				}
+				if envelope.Error.Status != test.expectedStatus || envelope.Error.Message == "" {
+					t.Fatalf("Unexpected error envelope content: %v", recorder.Body.String())
+				}
+				return
+			}
+
+			var result struct {
+				Boards []struct {
+					Name string `json:"name"`
+				} `json:"boards"`
+				MetaData struct {
+					TotalBoards int `json:"total_boards"`
+				} `json:"_metadata"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Unexpected response body: %v", recorder.Body.String())
+			}
+
+			if len(result.Boards) != len(test.expectedBoards) || result.MetaData.TotalBoards != len(test.expectedBoards) {
+				t.Fatalf("Unexpected boards: got %v, expected %v", recorder.Body.String(), test.expectedBoards)
+			}
+			for i, name := range test.expectedBoards {
+				if result.Boards[i].Name != name {
+					t.Fatalf("Unexpected board at %v: got %v, expected %v", i, result.Boards[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestApiHidesRootDirectory(t *testing.T) {
+	logger.Disable()
+
+	rootDir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(rootDir)
+	dir := filepath.Join(rootDir, "boards")
+	emptyDir := filepath.Join(rootDir, "empty")
+	for _, subDir := range []string{dir, emptyDir} {
+		if err := os.Mkdir(subDir, 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+	testutils.WriteToFile(t, filepath.Join(dir, "boards-1.json"), `{"name": "Board1", "vendor": "VendorA", "core": "CoreX"}`)
+	testutils.WriteToFile(t, filepath.Join(dir, "boards-2.json"), `{"name": "Board1", "vendor": "VendorA", "core": "CoreY"}`)
+
+	roots := web.Roots{{Name: "boards-root", Path: rootDir}}
+	if err := roots.Validate(); err != nil {
+		t.Fatalf("Unexpected roots validation err: %v", err.Error())
+	}
+	router := web.NewRouter(web.Config{Roots: roots}, rootDir)
+
+	tests := []struct {
+		name     string
+		target   string
+		expected []string
+	}{
+		{
+			name:     "Sources and conflicts",
+			target:   "/api/v1/boards?path=boards&sources=true",
+			expected: []string{`"file": "boards-root/boards/boards-1.json"`, `"file": "boards-root/boards/boards-2.json"`},
+		},
+		{
+			name:     "Error message",
+			target:   "/api/v1/boards?path=empty",
+			expected: []string{"no board files found in boards-root/empty"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.target, nil))
+
+			body := recorder.Body.String()
+			for _, expected := range test.expected {
+				if !strings.Contains(body, expected) {
+					t.Fatalf("Expected '%v' in response: %v", expected, body)
+				}
+			}
+			if strings.Contains(body, roots[0].Path) {
+				t.Fatalf("Unexpected root directory in response: %v", body)
+			}
+		})
+	}
+}
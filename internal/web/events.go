@@ -0,0 +1,96 @@
+package web
+
+import (
+	"boards-merger/internal/core"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// keepaliveInterval is the time between the comments keeping an idle stream open through proxies
+const keepaliveInterval = 15 * time.Second
+
+// handleProcessPathEvents streams the re-rendered boards table as Server-Sent Events whenever the processed directory changes.
+// The directory is watched for as long as a client showing it stays connected, by a single poller shared by its clients.
+func (srv *server) handleProcessPathEvents(w http.ResponseWriter, r *http.Request) {
+	if srv.config.Watch.Interval <= 0 {
+		http.Error(w, "Live updates are disabled", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	form := parseProcessForm(r)
+	data, path, files := srv.process(r.Context(), form)
+	if len(path) == 0 {
+		http.Error(w, data.Error, http.StatusBadRequest)
+		return
+	}
+
+	// The stream subscribes to the changes of the directory before it starts
+	key := watchKey{path: path, depth: form.depth}
+	if !form.recursive {
+		key.depth = 0
+	}
+	changes, unsubscribe := srv.watches.subscribe(key, files)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	previous := data.Result
+	onChange := func() {
+		data, _, found := srv.process(r.Context(), form)
+		// A failed read keeps watching the previously found files
+		if found != nil {
+			srv.watches.update(key, found)
+		}
+
+		data.Updated = time.Now().Format(time.TimeOnly)
+		if data.Result != nil {
+			if previous != nil {
+				data.Changes = core.DiffBoardsWithIdentity(previous, data.Result, srv.config.Identity).Summary()
+			}
+			previous = data.Result
+		}
+
+		var fragment bytes.Buffer
+		if err := GetTemplate().ExecuteTemplate(&fragment, "boards_result", data); err != nil {
+			fmt.Fprintf(&fragment, "<h3>Error generating boards result</h3>")
+		}
+		writeEvent(w, "boards", fragment.String())
+		flusher.Flush()
+	}
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changes:
+			onChange()
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes a single Server-Sent Event, every line of data is sent as its own 'data' field
+func writeEvent(w http.ResponseWriter, event string, data string) {
+	fmt.Fprintf(w, "event: %v\n", event)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(w, "data: %v\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
@@ -0,0 +1,223 @@
+package web_test
+
+import (
+	"boards-merger/internal/utils/logger"
+	"boards-merger/internal/utils/testutils"
+	"boards-merger/internal/watch"
+	"boards-merger/internal/web"
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessPathEvents(t *testing.T) {
+	logger.Disable()
+
+	rootDir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(rootDir)
+	testutils.WriteToFile(t, filepath.Join(rootDir, "boards-1.json"), `{"name": "Board1", "vendor": "VendorA"}`)
+
+	roots := web.Roots{{Name: "boards-root", Path: rootDir}}
+	if err := roots.Validate(); err != nil {
+		t.Fatalf("Unexpected roots validation err: %v", err.Error())
+	}
+	config := web.Config{Roots: roots, Watch: watch.Poller{Interval: 10 * time.Millisecond, Debounce: 20 * time.Millisecond}}
+	server := httptest.NewServer(web.NewRouter(config, rootDir))
+	// Close waits for the events handler, so it also checks that the watch stops when the client disconnects
+	defer server.Close()
+
+	form := url.Values{"root": {"boards-root"}, "recursive": {"on"}}
+	response, err := http.PostForm(server.URL+"/processPath", form)
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if !strings.Contains(string(body), `sse-connect="/processPath/events?`) {
+		t.Fatalf("Expected the table to subscribe to live updates: %v", string(body))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/processPath/events?"+form.Encode(), nil)
+	response, err = http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err.Error())
+	}
+	defer response.Body.Close()
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Unexpected content type: %v", contentType)
+	}
+
+	testutils.WriteToFile(t, filepath.Join(rootDir, "boards-2.json"), `{"name": "Board2", "vendor": "VendorB"}`)
+
+	var event strings.Builder
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			break
+		}
+		event.WriteString(line + "\n")
+	}
+
+	if !strings.HasPrefix(event.String(), "event: boards\n") {
+		t.Fatalf("Unexpected event: %v", event.String())
+	}
+	for _, expected := range []string{"Found 2 boards, from 2 vendors", "Board2", "1 added, 0 removed, 0 changed"} {
+		if !strings.Contains(event.String(), expected) {
+			t.Fatalf("Expected '%v' in event: %v", expected, event.String())
+		}
+	}
+	cancel()
+}
+
+func TestProcessPathEventsShared(t *testing.T) {
+	logger.Disable()
+
+	rootDir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(rootDir)
+	testutils.WriteToFile(t, filepath.Join(rootDir, "boards-1.json"), `{"name": "Board1", "vendor": "VendorA"}`)
+
+	roots := web.Roots{{Name: "boards-root", Path: rootDir}}
+	if err := roots.Validate(); err != nil {
+		t.Fatalf("Unexpected roots validation err: %v", err.Error())
+	}
+	config := web.Config{Roots: roots, Watch: watch.Poller{Interval: 10 * time.Millisecond, Debounce: 20 * time.Millisecond}}
+	server := httptest.NewServer(web.NewRouter(config, rootDir))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Clients of the same directory share its poller, each one receives its own table
+	wheres := []string{`vendor == "VendorA"`, `vendor == "VendorB"`}
+	streams := make([]*bufio.Scanner, len(wheres))
+	for i, where := range wheres {
+		form := url.Values{"root": {"boards-root"}, "where": {where}}
+		request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/processPath/events?"+form.Encode(), nil)
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("Unexpected err: %v", err.Error())
+		}
+		defer response.Body.Close()
+		streams[i] = bufio.NewScanner(response.Body)
+	}
+
+	testutils.WriteToFile(t, filepath.Join(rootDir, "boards-2.json"), `{"name": "Board2", "vendor": "VendorB"}`)
+
+	for i, expected := range []string{"Found 1 boards, from 1 vendors", "Board2"} {
+		var event strings.Builder
+		for streams[i].Scan() && len(streams[i].Text()) > 0 {
+			event.WriteString(streams[i].Text() + "\n")
+		}
+		if !strings.HasPrefix(event.String(), "event: boards\n") || !strings.Contains(event.String(), expected) {
+			t.Fatalf("Unexpected event of client %v, expected '%v': %v", i, expected, event.String())
+		}
+	}
+	cancel()
+}
+
+func TestProcessPathEventsDisabled(t *testing.T) {
+	rootDir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(rootDir)
+
+	roots := web.Roots{{Name: "boards-root", Path: rootDir}}
+	if err := roots.Validate(); err != nil {
+		t.Fatalf("Unexpected roots validation err: %v", err.Error())
+	}
+	router := web.NewRouter(web.Config{Roots: roots}, rootDir)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/processPath/events", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("Unexpected status: got %v, expected %v", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestProcessPathWhere(t *testing.T) {
+	logger.Disable()
+
+	rootDir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(rootDir)
+	testutils.WriteToFile(t, filepath.Join(rootDir, "boards-1.json"), `{"boards": [{"name": "Board1", "vendor": "VendorA", "has_wifi": true}, {"name": "Board2", "vendor": "VendorB"}]}`)
+
+	roots := web.Roots{{Name: "boards-root", Path: rootDir}}
+	if err := roots.Validate(); err != nil {
+		t.Fatalf("Unexpected roots validation err: %v", err.Error())
+	}
+	router := web.NewRouter(web.Config{Roots: roots}, rootDir)
+
+	tests := []struct {
+		name     string
+		where    string
+		expected []string
+		excluded []string
+	}{
+		{name: "Matching boards", where: `has_wifi && vendor =~ "A$"`, expected: []string{"Found 1 boards, from 1 vendors", "Board1"}, excluded: []string{"Board2"}},
+		{name: "Invalid expression", where: `has_wifi &&`, expected: []string{"invalid filter expression: unexpected end of expression at column 12"}, excluded: []string{"Board1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			form := url.Values{"root": {"boards-root"}, "where": {test.where}}
+			request := httptest.NewRequest(http.MethodPost, "/processPath", strings.NewReader(form.Encode()))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			body := recorder.Body.String()
+			for _, expected := range test.expected {
+				if !strings.Contains(body, expected) {
+					t.Fatalf("Expected '%v' in table: %v", expected, body)
+				}
+			}
+			for _, excluded := range test.excluded {
+				if strings.Contains(body, excluded) {
+					t.Fatalf("Unexpected '%v' in table: %v", excluded, body)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessPathHidesRootDirectory(t *testing.T) {
+	logger.Disable()
+
+	rootDir := testutils.CreateTempDir(t)
+	defer os.RemoveAll(rootDir)
+	testutils.WriteToFile(t, filepath.Join(rootDir, "boards-1.json"), `{"name": "Board1", "vendor": "VendorA", "core": "CoreX"}`)
+	testutils.WriteToFile(t, filepath.Join(rootDir, "boards-2.json"), `{"name": "Board1", "vendor": "VendorA", "core": "CoreY"}`)
+	testutils.WriteToFile(t, filepath.Join(rootDir, "broken.json"), `{"boards": [`)
+
+	roots := web.Roots{{Name: "boards-root", Path: rootDir}}
+	if err := roots.Validate(); err != nil {
+		t.Fatalf("Unexpected roots validation err: %v", err.Error())
+	}
+	router := web.NewRouter(web.Config{Roots: roots}, rootDir)
+
+	form := url.Values{"root": {"boards-root"}}
+	request := httptest.NewRequest(http.MethodPost, "/processPath", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body := recorder.Body.String()
+	// The tooltips, the conflicts panel and the diagnostics list
+	for _, expected := range []string{`title="boards-root/boards-1.json #0"`, "CoreY <span class=\"source\">boards-root/boards-2.json #0</span>", "boards-root/broken.json"} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("Expected '%v' in table: %v", expected, body)
+		}
+	}
+	if strings.Contains(body, roots[0].Path) {
+		t.Fatalf("Unexpected root directory in table: %v", body)
+	}
+}
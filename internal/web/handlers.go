@@ -2,18 +2,59 @@ package web
 
 import (
 	"boards-merger/internal/core"
+	"boards-merger/internal/diagnostics"
 	"boards-merger/internal/model"
+	"boards-merger/internal/query"
+	"boards-merger/internal/watch"
+	"context"
 	"fmt"
+	"html/template"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
-func StartWebServer(port string) error {
-	mux := http.NewServeMux()
+type Config struct {
+	Port  string
+	Roots Roots
+	// Timeout bounds the time spent reading and merging a directory per request, 0 disables it
+	Timeout time.Duration
+	// Watch polls the processed directories to push live updates of the HTML table, a zero interval disables it
+	Watch watch.Poller
+	// Aliases renames non-standard keys to the board fields of the processed files, it may be nil
+	Aliases *model.FieldAliases
+	// Coercion converts or rejects mistyped values of the typed fields, the zero value keeps them as extra entries
+	Coercion model.Coercion
+	// Vendors replaces vendor names by their canonical name, it may be nil
+	Vendors *model.VendorRegistry
+	// Identity matches the entries of a board and sorts the merged boards, the zero value uses the vendor and name
+	Identity model.IdentityKey
+	// Sort orders the merged boards before their identity, the zero value sorts them by identity only
+	Sort model.SortOrder
+	// GroupByVendor nests the boards under their vendor in the JSON & YAML API responses
+	GroupByVendor bool
+	// ExtendedMetaData adds statistics of the catalog to the metadata of the API responses
+	ExtendedMetaData bool
+	// Similarity reports and merges near-duplicate boards, the zero value disables it
+	Similarity core.SimilarityOptions
+}
+
+type server struct {
+	config Config
+	// watches polls the directories shown by live update streams
+	watches *watches
+}
 
-	mux.HandleFunc("/", handleRoot)
-	mux.HandleFunc("POST /processPath", handleProcessPath)
+func StartWebServer(config Config) error {
+	if len(config.Roots) == 0 {
+		config.Roots = Roots{{Name: "default", Path: "."}}
+	}
+	if err := config.Roots.Validate(); err != nil {
+		return err
+	}
 
 	// Setup the file server based on the executable path to avoid relative path and CWD problems
 	execPath, err := os.Executable()
@@ -23,48 +64,169 @@ func StartWebServer(port string) error {
 
 	basePath := filepath.Dir(execPath)
 	staticPath := filepath.Join(basePath, "../internal/web")
+
+	return http.ListenAndServe(":"+config.Port, NewRouter(config, staticPath))
+}
+
+// NewRouter expects config.Roots to be validated already
+func NewRouter(config Config, staticPath string) http.Handler {
+	srv := &server{config: config, watches: newWatches(config.Watch)}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", srv.handleRoot)
+	mux.HandleFunc("POST /processPath", srv.handleProcessPath)
+	mux.HandleFunc("GET /processPath/events", srv.handleProcessPathEvents)
+	srv.registerApiRoutes(mux)
 	mux.Handle("/static/", http.FileServer(http.Dir(staticPath)))
 
-	return http.ListenAndServe(":"+port, mux)
+	return mux
 }
 
-func handleRoot(w http.ResponseWriter, r *http.Request) {
+// requestContext is cancelled when the client disconnects or the configured timeout expires
+func (srv *server) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return srv.processContext(r.Context())
+}
+
+// processContext bounds a single directory processing with the configured timeout
+func (srv *server) processContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if srv.config.Timeout > 0 {
+		return context.WithTimeout(ctx, srv.config.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (srv *server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		Roots       Roots
+		Resolutions []model.Resolution
+	}{srv.config.Roots, model.Resolutions}
+
 	tmpl := GetTemplate()
-	if err := tmpl.ExecuteTemplate(w, "index.html", nil); err != nil {
+	if err := tmpl.ExecuteTemplate(w, "index.html", data); err != nil {
 		http.Error(w, "Error generating html.index", http.StatusInternalServerError)
 	}
 }
 
-func handleProcessPath(w http.ResponseWriter, r *http.Request) {
-	var data struct {
-		Error  string
-		Result *model.BoardsInfo
-	}
+// boardsTable is rendered by the boards_table.html template
+type boardsTable struct {
+	Error       string
+	Result      *model.BoardsInfo
+	Diagnostics []diagnostics.Diagnostic
+	// Live is the events URL streaming updates of the table, empty when live updates are disabled
+	Live template.URL
+	// Updated and Changes describe the last live update
+	Updated string
+	Changes string
+}
+
+// processForm holds the directory options of the form, sent as a POST body or as query parameters
+type processForm struct {
+	root      string
+	path      string
+	recursive bool
+	depth     int
+	strategy  string
+	overrides string
+	// where is a filter expression of the boards shown, empty shows every board
+	where string
+}
 
+func parseProcessForm(r *http.Request) processForm {
 	r.ParseForm()
-	path := r.FormValue("path")
-	recursive := r.FormValue("recursive") == "on"
-	depth := 10
-	fmt.Sscanf(r.FormValue("depth"), "%d", &depth)
+	form := processForm{
+		root:      r.FormValue("root"),
+		path:      r.FormValue("path"),
+		recursive: r.FormValue("recursive") == "on",
+		depth:     10,
+		strategy:  r.FormValue("strategy"),
+		overrides: r.FormValue("overrides"),
+		where:     strings.TrimSpace(r.FormValue("where")),
+	}
+	fmt.Sscanf(r.FormValue("depth"), "%d", &form.depth)
+	return form
+}
 
+func (form processForm) query() url.Values {
+	query := url.Values{}
+	query.Set("root", form.root)
+	query.Set("path", form.path)
+	if form.recursive {
+		query.Set("recursive", "on")
+	}
+	query.Set("depth", fmt.Sprint(form.depth))
+	query.Set("strategy", form.strategy)
+	query.Set("overrides", form.overrides)
+	query.Set("where", form.where)
+	return query
+}
+
+// process reads and merges the directory of the form, returning the resolved directory and its board files for watching.
+// The table names the files relative to the root, as '<root name>/<relative path>'.
+func (srv *server) process(ctx context.Context, form processForm) (data boardsTable, path string, files []string) {
+	var root Root
+	collector := diagnostics.NewCollector()
 	defer func() {
-		tmpl := GetTemplate()
-		if err := tmpl.ExecuteTemplate(w, "boards_table.html", data); err != nil {
-			http.Error(w, "Error generating boards result", http.StatusInternalServerError)
+		data.Diagnostics = root.displayDiagnostics(collector.Diagnostics(diagnostics.Warning))
+		data.Error = root.Hide(data.Error)
+		if data.Result != nil {
+			data.Result.RenameSourceFiles(root.Display)
 		}
 	}()
 
-	jsonList, err := core.ReadDirectory(path, recursive, depth)
+	strategy, err := model.ParseMergeStrategy(form.strategy + "," + form.overrides)
+	if err != nil {
+		data.Error = err.Error()
+		return data, "", nil
+	}
+
+	var where *query.Expression
+	if len(form.where) > 0 {
+		if where, err = query.Parse(form.where); err != nil {
+			data.Error = err.Error()
+			return data, "", nil
+		}
+	}
+
+	root, path, err = srv.config.Roots.Resolve(form.root, form.path)
 	if err != nil {
 		data.Error = err.Error()
-		return
+		return data, "", nil
 	}
 
-	boards, err := core.ProcessJsonFiles(jsonList)
+	ctx, cancel := srv.processContext(ctx)
+	defer cancel()
+
+	jsonList, err := core.ReadDirectoryContext(ctx, path, core.ReadOptions{Recursive: form.recursive, MaxDepth: form.depth, Diagnostics: collector})
 	if err != nil {
 		data.Error = err.Error()
-		return
+		return data, path, nil
 	}
 
+	// Sources are always tracked for the HTML table, they are shown as tooltips
+	boards, err := core.ProcessJsonFilesContext(ctx, jsonList, core.MergeOptions{Strategy: strategy, Provenance: true, Aliases: srv.config.Aliases, Coercion: srv.config.Coercion, Vendors: srv.config.Vendors, Identity: srv.config.Identity, Sort: srv.config.Sort, Similarity: srv.config.Similarity, Diagnostics: collector})
+	if err != nil {
+		data.Error = err.Error()
+		return data, path, jsonList
+	}
+
+	if where != nil {
+		boards = boards.Filter(where.Match)
+	}
 	data.Result = boards
+	return data, path, jsonList
+}
+
+func (srv *server) handleProcessPath(w http.ResponseWriter, r *http.Request) {
+	form := parseProcessForm(r)
+	data, path, _ := srv.process(r.Context(), form)
+
+	// The table subscribes to the changes of a resolved directory, even when it has no valid boards yet
+	if srv.config.Watch.Interval > 0 && len(path) > 0 {
+		data.Live = template.URL("/processPath/events?" + form.query().Encode())
+	}
+
+	tmpl := GetTemplate()
+	if err := tmpl.ExecuteTemplate(w, "boards_table.html", data); err != nil {
+		http.Error(w, "Error generating boards result", http.StatusInternalServerError)
+	}
 }
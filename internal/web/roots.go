@@ -0,0 +1,144 @@
+package web
+
+import (
+	"boards-merger/internal/diagnostics"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrUnknownRoot = errors.New("unknown root")
+	ErrOutsideRoot = errors.New("path is outside of the allowed root")
+)
+
+// Root is a named directory the web server is allowed to process, submitted paths are resolved inside it
+type Root struct {
+	Name string
+	Path string
+}
+
+type Roots []Root
+
+// String & Set implement flag.Value, roots are passed as repeated 'name=path' arguments
+func (roots *Roots) String() string {
+	specs := make([]string, 0, len(*roots))
+	for _, root := range *roots {
+		specs = append(specs, root.Name+"="+root.Path)
+	}
+	return strings.Join(specs, ", ")
+}
+
+func (roots *Roots) Set(spec string) error {
+	name, path, found := strings.Cut(spec, "=")
+	name = strings.TrimSpace(name)
+	if !found || len(name) == 0 || len(strings.TrimSpace(path)) == 0 {
+		return fmt.Errorf("invalid root '%v', expected 'name=path'", spec)
+	}
+
+	for _, root := range *roots {
+		if root.Name == name {
+			return fmt.Errorf("duplicate root name '%v'", name)
+		}
+	}
+
+	*roots = append(*roots, Root{Name: name, Path: path})
+	return nil
+}
+
+// Validate makes every root path absolute and symlink free, so later containment checks compare real paths
+func (roots Roots) Validate() error {
+	for i, root := range roots {
+		absPath, err := filepath.Abs(root.Path)
+		if err != nil {
+			return fmt.Errorf("failed to get an absolute path for root '%v': %v", root.Name, err.Error())
+		}
+
+		realPath, err := filepath.EvalSymlinks(absPath)
+		if err != nil {
+			return fmt.Errorf("invalid root '%v': %v", root.Name, err.Error())
+		}
+
+		info, err := os.Stat(realPath)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("root '%v' is not a directory: %v", root.Name, root.Path)
+		}
+
+		roots[i].Path = realPath
+	}
+	return nil
+}
+
+func (roots Roots) find(name string) (Root, error) {
+	// The root name can be omitted when a single root is configured
+	if name == "" && len(roots) == 1 {
+		return roots[0], nil
+	}
+
+	for _, root := range roots {
+		if root.Name == name {
+			return root, nil
+		}
+	}
+	return Root{}, fmt.Errorf("%w: '%v'", ErrUnknownRoot, name)
+}
+
+// Resolve maps a path submitted by a client onto the file system and returns it with its root, relative paths are
+// relative to the root, '..' escapes, absolute paths outside the root and symbolic links pointing outside of it are rejected
+func (roots Roots) Resolve(name string, path string) (Root, string, error) {
+	root, err := roots.find(name)
+	if err != nil {
+		return Root{}, "", err
+	}
+
+	resolved := filepath.Clean(path)
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(root.Path, resolved)
+	}
+
+	// Non existing paths are checked as is, and reported as invalid paths by the directory reader
+	if realPath, err := filepath.EvalSymlinks(resolved); err == nil {
+		resolved = realPath
+	}
+
+	relPath, err := filepath.Rel(root.Path, resolved)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return Root{}, "", fmt.Errorf("%w '%v': %v", ErrOutsideRoot, root.Name, path)
+	}
+
+	return root, resolved, nil
+}
+
+// Display names a path of the root as '<root name>/<relative path>' for clients, hiding where the root is on the server
+func (root Root) Display(path string) string {
+	relPath, err := filepath.Rel(root.Path, path)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return filepath.Base(path)
+	}
+	if relPath == "." {
+		return root.Name
+	}
+	return root.Name + "/" + filepath.ToSlash(relPath)
+}
+
+// Hide replaces the root directory by the root name in a message, such as an error naming the file it failed to read
+func (root Root) Hide(message string) string {
+	prefix := strings.TrimSuffix(root.Path, string(filepath.Separator))
+	if len(prefix) == 0 {
+		return message
+	}
+	return strings.ReplaceAll(message, prefix, root.Name)
+}
+
+// displayDiagnostics names the files of the findings, and the paths in their messages, relative to the root
+func (root Root) displayDiagnostics(findings []diagnostics.Diagnostic) []diagnostics.Diagnostic {
+	for i := range findings {
+		if len(findings[i].File) > 0 {
+			findings[i].File = root.Display(findings[i].File)
+		}
+		findings[i].Message = root.Hide(findings[i].Message)
+	}
+	return findings
+}
@@ -1,7 +1,9 @@
 package web
 
 import (
+	"boards-merger/internal/model"
 	"embed"
+	"fmt"
 	"html/template"
 )
 
@@ -12,10 +14,28 @@ var tmpl *template.Template
 
 func init() {
 	tmpl = template.Must(template.New("").Funcs(template.FuncMap{
-		"DerefBool": func(ptr *bool) bool { return *ptr },
+		"DerefBool":     func(ptr *bool) bool { return *ptr },
+		"SourceOf":      sourceOf,
+		"ExtraSourceOf": extraSourceOf,
 	}).ParseFS(views, "templates/*.html"))
 }
 
 func GetTemplate() *template.Template {
 	return tmpl
 }
+
+func sourceOf(board model.Board, field string) string {
+	return formatSource(board.Sources, field)
+}
+
+func extraSourceOf(board model.Board, key string) string {
+	return formatSource(board.ExtraSources, key)
+}
+
+func formatSource(sources map[string]model.Source, key string) string {
+	source, exists := sources[key]
+	if !exists {
+		return ""
+	}
+	return fmt.Sprintf("%v #%v", source.File, source.Index)
+}
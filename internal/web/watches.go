@@ -0,0 +1,87 @@
+package web
+
+import (
+	"boards-merger/internal/watch"
+	"context"
+	"sync"
+)
+
+// watchKey identifies a watched directory, the depth is 0 when it is not read recursively
+type watchKey struct {
+	path  string
+	depth int
+}
+
+// directoryWatch polls a directory once for every live update stream showing it
+type directoryWatch struct {
+	cancel      context.CancelFunc
+	files       []string
+	subscribers map[chan struct{}]struct{}
+}
+
+// watches shares one poller per directory between the live update streams, each stream re-renders its own table
+type watches struct {
+	poller watch.Poller
+	mu     sync.Mutex
+	byKey  map[watchKey]*directoryWatch
+}
+
+func newWatches(poller watch.Poller) *watches {
+	return &watches{poller: poller, byKey: make(map[watchKey]*directoryWatch)}
+}
+
+// subscribe notifies the returned channel of every change of the directory, a pending notification is not repeated.
+// The poller starts with the first subscriber and stops once the last one calls the returned unsubscribe function.
+func (w *watches) subscribe(key watchKey, files []string) (<-chan struct{}, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changes := make(chan struct{}, 1)
+	directory, exists := w.byKey[key]
+	if !exists {
+		ctx, cancel := context.WithCancel(context.Background())
+		directory = &directoryWatch{cancel: cancel, files: files, subscribers: make(map[chan struct{}]struct{})}
+		w.byKey[key] = directory
+		go w.poller.Run(ctx, func() []string { return w.paths(key, directory) }, func() { w.notify(directory) })
+	}
+	directory.subscribers[changes] = struct{}{}
+
+	unsubscribe := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(directory.subscribers, changes)
+		if len(directory.subscribers) == 0 && w.byKey[key] == directory {
+			directory.cancel()
+			delete(w.byKey, key)
+		}
+	}
+	return changes, unsubscribe
+}
+
+// update replaces the watched board files of the directory, after a stream read it again
+func (w *watches) update(key watchKey, files []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if directory, exists := w.byKey[key]; exists {
+		directory.files = files
+	}
+}
+
+// Directories are walked on every poll, so files added to a new subdirectory are noticed
+func (w *watches) paths(key watchKey, directory *directoryWatch) []string {
+	w.mu.Lock()
+	files := directory.files
+	w.mu.Unlock()
+	return append(watch.Paths(key.path, files), watch.Directories(key.path, key.depth)...)
+}
+
+func (w *watches) notify(directory *directoryWatch) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for changes := range directory.subscribers {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+}